@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-lang-jwt/helpers"
+	"go-lang-jwt/models"
+	"go-lang-jwt/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAllowedHosts handles GET /api/admin/allowed-hosts
+func GetAllowedHosts() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.CheckUserType(c, "ADMIN"); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		hosts, err := services.ListAllowedHosts(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"count":         len(hosts),
+			"allowed_hosts": hosts,
+		})
+	}
+}
+
+// AddAllowedHost handles POST /api/admin/allowed-hosts
+func AddAllowedHost() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.CheckUserType(c, "ADMIN"); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Parse request body
+		var req struct {
+			Host string `json:"host" binding:"required"`
+			Note string `json:"note"`
+		}
+
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "host field is required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		host, err := services.AddAllowedHost(ctx, c.GetString("user_id"), req.Host, req.Note)
+		if err != nil {
+			if err.Error() == "invalid host format" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err.Error() == "host already allowed" {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message":      "Host added to crawler allowlist",
+			"allowed_host": host,
+		})
+	}
+}
+
+// RemoveAllowedHost handles DELETE /api/admin/allowed-hosts/:id
+func RemoveAllowedHost() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.CheckUserType(c, "ADMIN"); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err := services.RemoveAllowedHost(ctx, c.Param("id"))
+		if err != nil {
+			if err.Error() == "invalid allowed host ID format" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err.Error() == "allowed host not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Host removed from crawler allowlist",
+		})
+	}
+}
+
+// SetExtractionRules handles PUT /api/admin/sources/:id/rules
+func SetExtractionRules() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.CheckUserType(c, "ADMIN"); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		var rules models.ExtractionRules
+		if err := c.BindJSON(&rules); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if validationErr := validate.Struct(rules); validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		source, err := services.SetExtractionRules(ctx, c.Param("id"), &rules)
+		if err != nil {
+			if err.Error() == "invalid source ID format" || strings.HasPrefix(err.Error(), "invalid extraction rules") {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err.Error() == "source not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Extraction rules saved",
+			"source":  source,
+		})
+	}
+}
+
+// RemoveExtractionRules handles DELETE /api/admin/sources/:id/rules
+func RemoveExtractionRules() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.CheckUserType(c, "ADMIN"); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		source, err := services.SetExtractionRules(ctx, c.Param("id"), nil)
+		if err != nil {
+			if err.Error() == "invalid source ID format" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err.Error() == "source not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Extraction rules removed",
+			"source":  source,
+		})
+	}
+}
+
+// PreviewSource handles POST /api/admin/sources/preview
+// Admin only, as it fetches whatever URL it is given
+func PreviewSource() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.CheckUserType(c, "ADMIN"); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		var req services.PreviewRequest
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "url field is required"})
+			return
+		}
+
+		if req.Rules != nil {
+			if validationErr := validate.Struct(req.Rules); validationErr != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		preview, err := services.PreviewExtraction(ctx, req)
+		if err != nil {
+			message := err.Error()
+			if message == "invalid URL format" ||
+				message == "URL points to a private or reserved address" ||
+				strings.HasPrefix(message, "invalid ") {
+				c.JSON(http.StatusBadRequest, gin.H{"error": message})
+				return
+			}
+			c.JSON(http.StatusBadGateway, gin.H{"error": message})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"count":   len(preview.Articles),
+			"preview": preview,
+		})
+	}
+}
+
+// UpdateSourceSettings handles PATCH /api/admin/sources/:id/settings
+func UpdateSourceSettings() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.CheckUserType(c, "ADMIN"); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		var settings services.SourceSettings
+		if err := c.BindJSON(&settings); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		source, err := services.UpdateSourceSettings(ctx, c.Param("id"), settings)
+		if err != nil {
+			if err.Error() == "invalid source ID format" || strings.HasPrefix(err.Error(), "invalid ") {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err.Error() == "source not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Source settings updated",
+			"source":  source,
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-lang-jwt/models"
+	"go-lang-jwt/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetArticle handles GET /api/articles/:id
+func GetArticle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user_id from JWT
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		article, err := services.GetArticleForUser(ctx, userID.(string), c.GetString("user_type") == "ADMIN", c.Param("id"))
+		if err != nil {
+			if err.Error() == "invalid article ID format" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err.Error() == "article not found or unauthorized" {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"article": article})
+	}
+}
+
+// GetArticleRevisions handles GET /api/articles/:id/revisions
+func GetArticleRevisions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user_id from JWT
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// Verify the user may access this article
+		article, err := services.GetArticleForUser(ctx, userID.(string), c.GetString("user_type") == "ADMIN", c.Param("id"))
+		if err != nil {
+			if err.Error() == "invalid article ID format" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err.Error() == "article not found or unauthorized" {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		versions, err := services.ListArticleVersions(ctx, article)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"article_id": article.ID,
+			"versions":   versions,
+		})
+	}
+}
+
+// MarkArticle handles PUT /api/articles/:id/star and PUT /api/articles/:id/save
+func MarkArticle(kind models.SavedArticleKind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user_id from JWT
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err := services.MarkArticle(ctx, userID.(string), c.GetString("user_type") == "ADMIN", c.Param("id"), kind)
+		if err != nil {
+			if err.Error() == "invalid article ID format" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err.Error() == "article not found or unauthorized" {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Article " + string(kind)})
+	}
+}
+
+// UnmarkArticle handles DELETE /api/articles/:id/star and DELETE /api/articles/:id/save
+func UnmarkArticle(kind models.SavedArticleKind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user_id from JWT
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := services.UnmarkArticle(ctx, userID.(string), c.Param("id"), kind); err != nil {
+			if err.Error() == "invalid article ID format" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Article no longer " + string(kind)})
+	}
+}
+
+// GetSavedArticles handles GET /api/saved
+func GetSavedArticles() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user_id from JWT
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return
+		}
+
+		// Optional kind filter: starred or saved
+		kind := models.SavedArticleKind(c.Query("kind"))
+		if kind != "" && kind != models.SavedArticleStarred && kind != models.SavedArticleSaved {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be starred or saved"})
+			return
+		}
+
+		// Get pagination params (default: page 1, limit 20)
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+		if page < 1 {
+			page = 1
+		}
+		if limit < 1 || limit > 100 {
+			limit = 20
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		saved, total, err := services.ListSavedArticles(ctx, userID.(string), kind, page, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		totalPages := (int(total) + limit - 1) / limit
+
+		c.JSON(http.StatusOK, gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+			"articles":    saved,
+		})
+	}
+}
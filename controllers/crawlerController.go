@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"time"
 
 	"go-lang-jwt/services"
@@ -54,20 +55,17 @@ func CrawlSubscription() gin.HandlerFunc {
 			return
 		}
 
-		// Trigger crawl in background goroutine
-		go func() {
-			crawlCtx, crawlCancel := context.WithTimeout(context.Background(), 5*time.Minute)
-			defer crawlCancel()
-
-			err := services.CrawlSource(crawlCtx, sourceID)
-			if err != nil {
-				// Log error but don't fail the request
-				// User already got "crawl started" response
-			}
-		}()
+		// Queue a crawl job for the worker pool
+		job, err := services.EnqueueCrawlJob(ctx, userID.(string), sourceID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Crawl started in background",
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": "Crawl queued",
+			"job_id":  job.ID,
+			"job":     job,
 		})
 	}
 }
@@ -99,17 +97,84 @@ func CrawlAllSubscriptions() gin.HandlerFunc {
 			return
 		}
 
-		// Trigger crawl in background goroutine
-		go func() {
-			crawlCtx, crawlCancel := context.WithTimeout(context.Background(), 30*time.Minute)
-			defer crawlCancel()
+		// Queue one crawl job per subscribed source
+		var jobIDs []primitive.ObjectID
+		for _, sub := range subscriptions {
+			job, err := services.EnqueueCrawlJob(ctx, userID.(string), sub.Source.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			jobIDs = append(jobIDs, job.ID)
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": "Crawl queued for all subscriptions",
+			"count":   len(subscriptions),
+			"job_ids": jobIDs,
+		})
+	}
+}
+
+// GetCrawlJob handles GET /api/crawl/jobs/:id
+func GetCrawlJob() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user_id from JWT
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		job, err := services.GetCrawlJob(ctx, userID.(string), c.GetString("user_type") == "ADMIN", c.Param("id"))
+		if err != nil {
+			if err.Error() == "invalid job ID format" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err.Error() == "job not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"job": job})
+	}
+}
+
+// ListCrawlJobs handles GET /api/crawl/jobs
+func ListCrawlJobs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user_id from JWT
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return
+		}
+
+		// Number of recent jobs to return (default 20)
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+		if limit < 1 || limit > 100 {
+			limit = 20
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
 
-			services.CrawlUserSources(crawlCtx, userID.(string))
-		}()
+		jobs, err := services.ListCrawlJobs(ctx, userID.(string), limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"message": "Crawl started for all subscriptions in background",
-			"count":   len(subscriptions),
+			"count": len(jobs),
+			"jobs":  jobs,
 		})
 	}
 }
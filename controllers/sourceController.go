@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-lang-jwt/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DiscoverSource handles POST /api/sources/:id/discover
+func DiscoverSource() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user_id from JWT
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		// Verify the user may access this source
+		source, err := services.GetSourceForUser(ctx, userID.(string), c.GetString("user_type") == "ADMIN", c.Param("id"))
+		if err != nil {
+			if err.Error() == "invalid source ID format" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err.Error() == "source not found or unauthorized" {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		updated, err := services.RefreshSourceDiscovery(ctx, source.ID)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Source discovery completed",
+			"source":  updated,
+		})
+	}
+}
+
+// GetSourceCrawls handles GET /api/sources/:id/crawls
+func GetSourceCrawls() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user_id from JWT
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+			return
+		}
+
+		// Get pagination params (default: page 1, limit 20)
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+		if page < 1 {
+			page = 1
+		}
+		if limit < 1 || limit > 100 {
+			limit = 20
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// Verify the user may access this source
+		source, err := services.GetSourceForUser(ctx, userID.(string), c.GetString("user_type") == "ADMIN", c.Param("id"))
+		if err != nil {
+			if err.Error() == "invalid source ID format" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err.Error() == "source not found or unauthorized" {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		runs, total, err := services.ListCrawlRuns(ctx, source.ID, page, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		totalPages := (int(total) + limit - 1) / limit
+
+		c.JSON(http.StatusOK, gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+			"crawls":      runs,
+		})
+	}
+}
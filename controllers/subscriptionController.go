@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"go-lang-jwt/services"
@@ -30,14 +31,14 @@ func AddSubscription() gin.HandlerFunc {
 			return
 		}
 
-		// Create context with timeout
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		// Create context with timeout (new sources run feed discovery)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
 		// Call service
 		subscription, err := services.AddSubscription(ctx, userID.(string), req.URL)
 		if err != nil {
-			if err.Error() == "invalid URL format" {
+			if strings.HasPrefix(err.Error(), "invalid URL") || err.Error() == "URL points to a private or reserved address" {
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 				return
 			}
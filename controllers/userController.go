@@ -20,7 +20,11 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-var userCollection *mongo.Collection = database.OpenCollection(database.Client, "user")
+// userCollection opens the users collection once main has connected
+func userCollection() *mongo.Collection {
+	return database.OpenCollection(database.Client, "user")
+}
+
 var validate = validator.New()
 
 func HashPassword(password string) string {
@@ -63,7 +67,7 @@ func Signup() gin.HandlerFunc {
 			return
 		}
 
-		count, err := userCollection.CountDocuments(ctx, bson.M{"email": user.Email})
+		count, err := userCollection().CountDocuments(ctx, bson.M{"email": user.Email})
 		if err != nil {
 			log.Panic(err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "error occurred during checking for email"})
@@ -75,7 +79,7 @@ func Signup() gin.HandlerFunc {
 			return
 		}
 
-		count2, err := userCollection.CountDocuments(ctx, bson.M{"phone": user.Phone})
+		count2, err := userCollection().CountDocuments(ctx, bson.M{"phone": user.Phone})
 		if err != nil {
 			log.Panic(err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "error occurred during checking the phone number"})
@@ -98,7 +102,7 @@ func Signup() gin.HandlerFunc {
 		user.Token = &token
 		user.Refresh_token = &refreshToken
 
-		resultInsertionNumber, insertErr := userCollection.InsertOne(ctx, user)
+		resultInsertionNumber, insertErr := userCollection().InsertOne(ctx, user)
 		if insertErr != nil {
 			msg := fmt.Sprintf("User item was not created")
 			c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
@@ -122,7 +126,7 @@ func Login() gin.HandlerFunc {
 			return
 		}
 
-		err := userCollection.FindOne(ctx, bson.M{"email": user.Email}).Decode(&foundUser)
+		err := userCollection().FindOne(ctx, bson.M{"email": user.Email}).Decode(&foundUser)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "email or password is incorrect"})
 			return
@@ -143,7 +147,7 @@ func Login() gin.HandlerFunc {
 
 		helpers.UpdateAllTokens(token, refreshToken, foundUser.User_id)
 
-		err = userCollection.FindOne(ctx, bson.M{"user_id": foundUser.User_id}).Decode(&foundUser)
+		err = userCollection().FindOne(ctx, bson.M{"user_id": foundUser.User_id}).Decode(&foundUser)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -187,7 +191,7 @@ func GetUsers() gin.HandlerFunc {
 				{Key: "total_count", Value: 1},
 				{Key: "user_items", Value: bson.D{{Key: "$slice", Value: []interface{}{"$data", startIndex, recordPerPage}}}}}}}
 
-		result, err := userCollection.Aggregate(ctx, mongo.Pipeline{
+		result, err := userCollection().Aggregate(ctx, mongo.Pipeline{
 			matchStage, groupStage, projectStage})
 
 		if err != nil {
@@ -217,7 +221,7 @@ func GetUser() gin.HandlerFunc {
 		defer cancel()
 
 		var user models.User
-		err := userCollection.FindOne(ctx, bson.M{"user_id": userId}).Decode(&user)
+		err := userCollection().FindOne(ctx, bson.M{"user_id": userId}).Decode(&user)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -12,10 +12,12 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func DBinstance() *mongo.Client {
-	err := godotenv.Load(".env")
+// envErr is the result of loading .env, done before any package reads its settings
+// DBinstance fails on it, so only code that connects needs the file (tests don't)
+var envErr = godotenv.Load(".env")
 
-	if err != nil {
+func DBinstance() *mongo.Client {
+	if envErr != nil {
 		log.Fatal("Error loading .env file")
 	}
 
@@ -41,7 +43,8 @@ func DBinstance() *mongo.Client {
 	return client
 }
 
-var Client *mongo.Client = DBinstance()
+// Client is connected by main through DBinstance before anything uses it
+var Client *mongo.Client
 
 func OpenCollection(client *mongo.Client, collectionName string) *mongo.Collection {
 	var collection *mongo.Collection = client.Database("cluster0").Collection(collectionName)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -22,9 +23,18 @@ func createSourceIndexes(collection *mongo.Collection) error {
 		Options: options.Index().SetUnique(true).SetName("url_unique"),
 	}
 
-	_, err := collection.Indexes().CreateOne(ctx, urlIndex)
+	// Index on next_crawl_at for the scheduler's due-source query
+	nextCrawlIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "next_crawl_at", Value: 1}},
+		Options: options.Index().SetName("next_crawl_at_idx"),
+	}
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		urlIndex,
+		nextCrawlIndex,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create source url index: %v", err)
+		return fmt.Errorf("failed to create source indexes: %v", err)
 	}
 
 	log.Println("✓ Source indexes created successfully")
@@ -78,12 +88,49 @@ func createArticleIndexes(collection *mongo.Collection) error {
 		Options: options.Index().SetUnique(true).SetName("source_url_unique"),
 	}
 
+	// Compound unique index on (source_id, canonical_url) - dedupe URL variants per source
+	// Partial, so articles saved before canonical URLs existed don't collide
+	sourceCanonicalIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "source_id", Value: 1},
+			{Key: "canonical_url", Value: 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetName("source_canonical_url_unique").
+			SetPartialFilterExpression(bson.M{"canonical_url": bson.M{"$gt": ""}}),
+	}
+
 	// Simple index on source_id for finding all articles from a source
 	sourceIndex := mongo.IndexModel{
 		Keys:    bson.D{{Key: "source_id", Value: 1}},
 		Options: options.Index().SetName("source_id_idx"),
 	}
 
+	// Compound index on (source_id, discovered_at) for the retention sweeper's per-source ordering
+	sourceDiscoveredIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "source_id", Value: 1},
+			{Key: "discovered_at", Value: -1},
+		},
+		Options: options.Index().SetName("source_discovered_desc"),
+	}
+
+	// Multikey index on source_ids so the feed finds articles linked from other sources
+	sourceIdsIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "source_ids", Value: 1}},
+		Options: options.Index().SetName("source_ids_idx"),
+	}
+
+	// Compound index on (source_ids, discovered_at) for the retention sweeper's ordering of linked articles
+	sourceIdsDiscoveredIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "source_ids", Value: 1},
+			{Key: "discovered_at", Value: -1},
+		},
+		Options: options.Index().SetName("source_ids_discovered_desc"),
+	}
+
 	// Descending index on published_at for sorting newest articles first
 	publishedIndex := mongo.IndexModel{
 		Keys:    bson.D{{Key: "published_at", Value: -1}},
@@ -96,6 +143,12 @@ func createArticleIndexes(collection *mongo.Collection) error {
 		Options: options.Index().SetName("content_hash_idx"),
 	}
 
+	// Multikey index on simhash_bands for the near-duplicate candidate lookup
+	simhashIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "simhash_bands", Value: 1}},
+		Options: options.Index().SetName("simhash_bands_idx"),
+	}
+
 	// Descending index on discovered_at for tracking recent discoveries
 	discoveredIndex := mongo.IndexModel{
 		Keys:    bson.D{{Key: "discovered_at", Value: -1}},
@@ -105,9 +158,14 @@ func createArticleIndexes(collection *mongo.Collection) error {
 	// Create all indexes at once
 	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		sourceUrlIndex,
+		sourceCanonicalIndex,
 		sourceIndex,
+		sourceDiscoveredIndex,
+		sourceIdsIndex,
+		sourceIdsDiscoveredIndex,
 		publishedIndex,
 		contentHashIndex,
+		simhashIndex,
 		discoveredIndex,
 	})
 	if err != nil {
@@ -118,6 +176,188 @@ func createArticleIndexes(collection *mongo.Collection) error {
 	return nil
 }
 
+// createSavedArticleIndexes creates indexes for saved_articles collection
+func createSavedArticleIndexes(collection *mongo.Collection) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Compound unique index on (user_id, article_id, kind) - star or save an article once
+	userArticleIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "article_id", Value: 1},
+			{Key: "kind", Value: 1},
+		},
+		Options: options.Index().SetUnique(true).SetName("user_article_kind_unique"),
+	}
+
+	// Compound index on (user_id, created_at) for listing a user's saved articles
+	userCreatedIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "created_at", Value: -1},
+		},
+		Options: options.Index().SetName("user_created_desc"),
+	}
+
+	// Index on article_id so the retention sweeper can spare saved articles
+	articleIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "article_id", Value: 1}},
+		Options: options.Index().SetName("article_id_idx"),
+	}
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		userArticleIndex,
+		userCreatedIndex,
+		articleIndex,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create saved article indexes: %v", err)
+	}
+
+	log.Println("✓ Saved article indexes created successfully")
+	return nil
+}
+
+// createAllowedHostIndexes creates indexes for allowed_hosts collection
+func createAllowedHostIndexes(collection *mongo.Collection) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Unique index on host (each intranet host is approved once)
+	hostIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "host", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("host_unique"),
+	}
+
+	_, err := collection.Indexes().CreateOne(ctx, hostIndex)
+	if err != nil {
+		return fmt.Errorf("failed to create allowed host index: %v", err)
+	}
+
+	log.Println("✓ Allowed host indexes created successfully")
+	return nil
+}
+
+// createCrawlJobIndexes creates indexes for crawl_jobs collection
+func createCrawlJobIndexes(collection *mongo.Collection) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Compound index on (status, created_at) for workers claiming the oldest queued job
+	statusIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "status", Value: 1},
+			{Key: "created_at", Value: 1},
+		},
+		Options: options.Index().SetName("status_created_idx"),
+	}
+
+	// Compound index on (user_id, created_at) for listing a user's recent jobs
+	userIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "created_at", Value: -1},
+		},
+		Options: options.Index().SetName("user_created_desc"),
+	}
+
+	// Compound index on (source_id, status) so the scheduler can skip sources with a pending job
+	sourceStatusIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "source_id", Value: 1},
+			{Key: "status", Value: 1},
+		},
+		Options: options.Index().SetName("source_status_idx"),
+	}
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		statusIndex,
+		userIndex,
+		sourceStatusIndex,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create crawl job indexes: %v", err)
+	}
+
+	log.Println("✓ Crawl job indexes created successfully")
+	return nil
+}
+
+// createArticleRevisionIndexes creates indexes for article_revisions collection
+func createArticleRevisionIndexes(collection *mongo.Collection) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Compound unique index on (article_id, version) - one revision per replaced version
+	articleVersionIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "article_id", Value: 1},
+			{Key: "version", Value: -1},
+		},
+		Options: options.Index().SetUnique(true).SetName("article_version_unique"),
+	}
+
+	_, err := collection.Indexes().CreateOne(ctx, articleVersionIndex)
+	if err != nil {
+		return fmt.Errorf("failed to create article revision indexes: %v", err)
+	}
+
+	log.Println("✓ Article revision indexes created successfully")
+	return nil
+}
+
+// crawlRunRetention reads CRAWL_RUN_RETENTION (a Go duration, default 30 days)
+func crawlRunRetention() time.Duration {
+	retention, err := time.ParseDuration(os.Getenv("CRAWL_RUN_RETENTION"))
+	if err != nil || retention <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return retention
+}
+
+// createCrawlRunIndexes creates indexes for crawl_runs collection
+func createCrawlRunIndexes(collection *mongo.Collection) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Compound index on (source_id, started_at) for a source's crawl history
+	sourceIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "source_id", Value: 1},
+			{Key: "started_at", Value: -1},
+		},
+		Options: options.Index().SetName("source_started_desc"),
+	}
+
+	_, err := collection.Indexes().CreateOne(ctx, sourceIndex)
+	if err != nil {
+		return fmt.Errorf("failed to create crawl run indexes: %v", err)
+	}
+
+	// TTL index on finished_at - MongoDB deletes runs older than the retention
+	retentionSeconds := int32(crawlRunRetention() / time.Second)
+	ttlIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "finished_at", Value: 1}},
+		Options: options.Index().SetName("finished_at_ttl").SetExpireAfterSeconds(retentionSeconds),
+	}
+
+	_, err = collection.Indexes().CreateOne(ctx, ttlIndex)
+	if err != nil {
+		// The index exists with another retention; update it in place
+		err = collection.Database().RunCommand(ctx, bson.D{
+			{Key: "collMod", Value: collection.Name()},
+			{Key: "index", Value: bson.M{"name": "finished_at_ttl", "expireAfterSeconds": retentionSeconds}},
+		}).Err()
+		if err != nil {
+			return fmt.Errorf("failed to update crawl run retention: %v", err)
+		}
+	}
+
+	log.Println("✓ Crawl run indexes created successfully")
+	return nil
+}
+
 // EnsureIndexes creates all necessary indexes for the application
 func EnsureIndexes() error {
 	log.Println("Creating database indexes...")
@@ -126,6 +366,11 @@ func EnsureIndexes() error {
 	sourceCollection := OpenCollection(Client, "sources")
 	subscriptionCollection := OpenCollection(Client, "subscriptions")
 	articleCollection := OpenCollection(Client, "articles")
+	articleRevisionCollection := OpenCollection(Client, "article_revisions")
+	savedArticleCollection := OpenCollection(Client, "saved_articles")
+	allowedHostCollection := OpenCollection(Client, "allowed_hosts")
+	crawlJobCollection := OpenCollection(Client, "crawl_jobs")
+	crawlRunCollection := OpenCollection(Client, "crawl_runs")
 
 	// Create indexes for each collection
 	if err := createSourceIndexes(sourceCollection); err != nil {
@@ -140,6 +385,26 @@ func EnsureIndexes() error {
 		return err
 	}
 
+	if err := createArticleRevisionIndexes(articleRevisionCollection); err != nil {
+		return err
+	}
+
+	if err := createSavedArticleIndexes(savedArticleCollection); err != nil {
+		return err
+	}
+
+	if err := createAllowedHostIndexes(allowedHostCollection); err != nil {
+		return err
+	}
+
+	if err := createCrawlJobIndexes(crawlJobCollection); err != nil {
+		return err
+	}
+
+	if err := createCrawlRunIndexes(crawlRunCollection); err != nil {
+		return err
+	}
+
 	log.Println("✓ All indexes created successfully!")
 	return nil
 }
@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// SimHashBandCount is the number of 8-bit bands a fingerprint is split into
+// Two fingerprints within SimHashBandCount-1 bits of each other share at least one band
+const SimHashBandCount = 8
+
+// GenerateSimHash creates a 64-bit SimHash from title and content
+// Similar texts get fingerprints a small Hamming distance apart, so a changed word
+// or an appended "(Updated)" still matches; words and word pairs are the features
+func GenerateSimHash(title string, content string) uint64 {
+	words := strings.FieldsFunc(strings.ToLower(title+" "+content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	addFeature := func(feature string) {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(feature))
+		sum := hasher.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<uint(bit)) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	for i, word := range words {
+		addFeature(word)
+		if i > 0 {
+			addFeature(words[i-1] + " " + word)
+		}
+	}
+
+	var fingerprint uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << uint(bit)
+		}
+	}
+	return fingerprint
+}
+
+// SimHashBands splits a fingerprint into indexable bands, each tagged with its position
+// (band i holding byte b is stored as i<<8 | b)
+func SimHashBands(fingerprint uint64) []int32 {
+	bands := make([]int32, SimHashBandCount)
+	for i := range bands {
+		bands[i] = int32(i<<8) | int32((fingerprint>>(uint(i)*8))&0xff)
+	}
+	return bands
+}
+
+// HammingDistance counts the bits two fingerprints differ in
+func HammingDistance(a uint64, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,101 @@
+package helpers
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSimHashBands(t *testing.T) {
+	bands := SimHashBands(0x0807060504030201)
+	for i, band := range bands {
+		if want := int32(i<<8 | (i + 1)); band != want {
+			t.Errorf("band %d = %#x, want %#x", i, band, want)
+		}
+	}
+
+	// The same byte in different positions must not collide
+	seen := map[int32]bool{}
+	for _, band := range SimHashBands(0) {
+		if seen[band] {
+			t.Errorf("band %#x repeated for an all-zero fingerprint", band)
+		}
+		seen[band] = true
+	}
+}
+
+// sharesBand reports whether two fingerprints would meet in the simhash_bands index
+func sharesBand(a uint64, b uint64) bool {
+	bandsA := SimHashBands(a)
+	for i, band := range SimHashBands(b) {
+		if bandsA[i] == band {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSimHashBandsFindCloseFingerprints(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for trial := 0; trial < 1000; trial++ {
+		fingerprint := random.Uint64()
+		near := fingerprint
+		for _, bit := range random.Perm(64)[:SimHashBandCount-1] {
+			near ^= 1 << uint(bit)
+		}
+
+		if !sharesBand(fingerprint, near) {
+			t.Fatalf("%#x and %#x are %d bits apart but share no band", fingerprint, near, HammingDistance(fingerprint, near))
+		}
+	}
+
+	// One flipped bit in every byte is the smallest change the bands can miss
+	if sharesBand(0, 0x0101010101010101) {
+		t.Error("fingerprints differing in every band share one")
+	}
+}
+
+func TestGenerateSimHash(t *testing.T) {
+	title := "City council approves new budget for schools"
+	content := "The city council voted on Tuesday to approve a new budget that raises funding for public schools, " +
+		"adds two libraries and repairs roads in the northern districts after months of debate."
+
+	original := GenerateSimHash(title, content)
+
+	if got := GenerateSimHash("CITY council, approves: new budget for schools!", content); got != original {
+		t.Errorf("case and punctuation changed the fingerprint: %#x vs %#x", got, original)
+	}
+
+	updated := GenerateSimHash(title+" (Updated)", content)
+	if distance := HammingDistance(original, updated); distance >= SimHashBandCount {
+		t.Errorf("updated title is %d bits away, want fewer than %d", distance, SimHashBandCount)
+	}
+
+	unrelated := GenerateSimHash("Local team wins the championship final",
+		"Fans filled the streets on Sunday night after the home team won the final in extra time.")
+	if distance := HammingDistance(original, unrelated); distance < 16 {
+		t.Errorf("unrelated article is only %d bits away", distance)
+	}
+
+	if got := GenerateSimHash("", " -- "); got != 0 {
+		t.Errorf("fingerprint without words = %#x, want 0", got)
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0xff, 0x0f, 4},
+		{0, ^uint64(0), 64},
+	}
+
+	for _, tt := range tests {
+		if got := HammingDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("HammingDistance(%#x, %#x) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -24,7 +24,11 @@ type SignedDetails struct {
 	jwt.StandardClaims
 }
 
-var userCollection *mongo.Collection = database.OpenCollection(database.Client, "user")
+// userCollection opens the users collection once main has connected
+func userCollection() *mongo.Collection {
+	return database.OpenCollection(database.Client, "user")
+}
+
 var SECRET_KEY string = os.Getenv("SECRET_KEY")
 
 func GenerateAllTokens(email string, firstName string, lastName string, userType string, uid string) (signedToken string, signedRefreshToken string, err error) {
@@ -108,7 +112,7 @@ func UpdateAllTokens(signedToken string, signedRefreshToken string, userId strin
 		Upsert: &upsert,
 	}
 
-	_, err := userCollection.UpdateOne(
+	_, err := userCollection().UpdateOne(
 		ctx,
 		filter,
 		bson.D{
@@ -0,0 +1,91 @@
+package helpers
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// trackingParams are query parameters removed from canonical URLs;
+// a trailing * matches any parameter with that prefix
+var trackingParams = GetEnvList("TRACKING_PARAMS",
+	"utm_*,fbclid,gclid,dclid,msclkid,yclid,mc_cid,mc_eid,_ga,_gl,igshid,ref,ref_src,ref_url,spm,cmpid,__twitter_impression")
+
+// CanonicalizeURL normalizes an article URL so variants of the same page compare equal:
+// https scheme, lowercase host without default port or trailing dot, no fragment,
+// no tracking parameters, sorted query and no trailing slash on non-root paths
+func CanonicalizeURL(rawURL string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+
+	scheme := strings.ToLower(parsed.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", errors.New("not an http(s) URL")
+	}
+	if parsed.Host == "" {
+		return "", errors.New("URL has no host")
+	}
+
+	// http and https variants are the same article
+	parsed.Scheme = "https"
+
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	port := parsed.Port()
+	if port == "80" || port == "443" {
+		port = ""
+	}
+	if port != "" {
+		parsed.Host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		parsed.Host = "[" + host + "]" // IPv6 literal
+	} else {
+		parsed.Host = host
+	}
+
+	parsed.User = nil
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+
+	// Trim on the escaped path so encoded characters like %2F survive
+	escapedPath := strings.TrimRight(parsed.EscapedPath(), "/")
+	if escapedPath == "" {
+		escapedPath = "/"
+	}
+	if unescaped, err := url.PathUnescape(escapedPath); err == nil {
+		parsed.Path = unescaped
+		parsed.RawPath = escapedPath
+	}
+
+	// url.Values.Encode sorts by key; repeated values are sorted as well
+	query := parsed.Query()
+	for key := range query {
+		if isTrackingParam(key) {
+			delete(query, key)
+		}
+	}
+	for _, values := range query {
+		sort.Strings(values)
+	}
+	parsed.RawQuery = query.Encode()
+	parsed.ForceQuery = false
+
+	return parsed.String(), nil
+}
+
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	for _, param := range trackingParams {
+		if prefix, ok := strings.CutSuffix(param, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == param {
+			return true
+		}
+	}
+	return false
+}
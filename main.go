@@ -1,18 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 
 	"go-lang-jwt/database"
 	"go-lang-jwt/routes"
+	"go-lang-jwt/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
 	fmt.Println("Starting JWT Authentication Server...")
+	database.Client = database.DBinstance()
+
 	port := os.Getenv("PORT")
 
 	if port == "" {
@@ -24,6 +28,15 @@ func main() {
 		log.Fatal("Failed to create indexes: ", err)
 	}
 
+	// Process queued crawl jobs in the background
+	services.StartCrawlWorkers(context.Background())
+
+	// Enqueue crawls for sources that are due
+	services.StartCrawlScheduler(context.Background())
+
+	// Delete articles the retention policies no longer keep
+	services.StartRetentionSweeper(context.Background())
+
 	router := gin.New()
 	router.Use(gin.Logger())
 
@@ -33,13 +46,16 @@ func main() {
 	// Protected routes (authentication required)
 	routes.UserRoutes(router)
 	routes.SubscriptionRoutes(router)
+	routes.SourceRoutes(router)
+	routes.ArticleRoutes(router)
+	routes.AdminRoutes(router)
 
 	// ADD THIS DEBUG CODE:
 	fmt.Println("\n=== Registered Routes ===")
 	for _, route := range router.Routes() {
 		log.Printf("%s %s", route.Method, route.Path)
 	}
-	fmt.Println("========================\n")
+	fmt.Print("========================\n\n")
 
 	fmt.Printf("Server is running on port %s\n", port)
 
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AllowedHost is an admin-approved host the crawler may fetch even when it
+// resolves to a private address (e.g. an intranet news site)
+type AllowedHost struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	Host       string             `bson:"host" json:"host" validate:"required,max=253"`
+	Note       string             `bson:"note" json:"note" validate:"max=500"`
+	Created_by string             `bson:"created_by" json:"created_by"`
+	Created_at time.Time          `bson:"created_at" json:"created_at"`
+}
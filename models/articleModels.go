@@ -11,10 +11,39 @@ type Article struct {
 	ID            primitive.ObjectID `bson:"_id" json:"id"`
 	Source_id     primitive.ObjectID `bson:"source_id" json:"source_id" validate:"required"`
 	Title         string             `bson:"title" json:"title" validate:"required,min=1,max=500"`
-	URL           string             `bson:"url" json:"url" validate:"required,url,max=2000"`
+	URL           string             `bson:"url" json:"url" validate:"required,url,max=2000"`                  // as linked by the source
+	Canonical_url string             `bson:"canonical_url" json:"canonical_url" validate:"omitempty,max=2000"` // normalized, used for dedupe
+	Guid          *string            `bson:"guid" json:"guid"`
 	Content_hash  string             `bson:"content_hash" json:"content_hash" validate:"required,len=64"`
 	Summary       *string            `bson:"summary" json:"summary" validate:"omitempty,max=1000"`
 	Published_at  *time.Time         `bson:"published_at" json:"published_at"`
 	Discovered_at time.Time          `bson:"discovered_at" json:"discovered_at"`
+	Updated_at    *time.Time         `bson:"updated_at" json:"updated_at"` // last time the publisher's edit was picked up
+	Version       int                `bson:"version" json:"version"`       // earlier versions are in article_revisions
 	Author        *string            `bson:"author" json:"author" validate:"omitempty,max=200"`
+	Image_url     *string            `bson:"image_url" json:"image_url" validate:"omitempty,max=2000"`
+	Section       *string            `bson:"section" json:"section" validate:"omitempty,max=200"`
+
+	// SimHash fingerprint (uint64 bits stored as int64) and its bands for the indexed lookup;
+	// near_duplicate_of points at the original when another article is within the source's distance
+	Simhash           int64               `bson:"simhash" json:"simhash"`
+	Simhash_bands     []int32             `bson:"simhash_bands" json:"-"`
+	Near_duplicate_of *primitive.ObjectID `bson:"near_duplicate_of" json:"near_duplicate_of"`
+
+	// Every source that carried the article (source_id first); mentions records the others
+	Source_ids []primitive.ObjectID `bson:"source_ids" json:"source_ids"`
+	Mentions   []ArticleMention     `bson:"mentions" json:"mentions"`
+
+	// Main content, for sources in readability mode; left out of the feed
+	Content_html         *string `bson:"content_html,omitempty" json:"content_html,omitempty"` // sanitized
+	Content_text         *string `bson:"content_text,omitempty" json:"content_text,omitempty"`
+	Word_count           int     `bson:"word_count" json:"word_count"`
+	Reading_time_minutes int     `bson:"reading_time_minutes" json:"reading_time_minutes"`
+}
+
+// ArticleMention records another source publishing the same content
+type ArticleMention struct {
+	Source_id     primitive.ObjectID `bson:"source_id" json:"source_id"`
+	URL           string             `bson:"url" json:"url"`
+	Discovered_at time.Time          `bson:"discovered_at" json:"discovered_at"`
 }
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ArticleRevision is an earlier version of an article, saved when the publisher edited it
+type ArticleRevision struct {
+	ID           primitive.ObjectID `bson:"_id" json:"id"`
+	Article_id   primitive.ObjectID `bson:"article_id" json:"article_id"`
+	Version      int                `bson:"version" json:"version"`
+	Title        string             `bson:"title" json:"title"`
+	Summary      *string            `bson:"summary" json:"summary"`
+	Content_hash string             `bson:"content_hash" json:"content_hash"`
+	Seen_at      time.Time          `bson:"seen_at" json:"seen_at"`         // when this version was first crawled
+	Replaced_at  time.Time          `bson:"replaced_at" json:"replaced_at"` // when a newer version replaced it
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CrawlJobStatus string
+
+const (
+	CrawlJobQueued    CrawlJobStatus = "queued"
+	CrawlJobRunning   CrawlJobStatus = "running"
+	CrawlJobSucceeded CrawlJobStatus = "succeeded"
+	CrawlJobFailed    CrawlJobStatus = "failed"
+	CrawlJobSkipped   CrawlJobStatus = "skipped" // another instance was already crawling the source
+)
+
+// CrawlJob is a persisted request to crawl one source, processed by the worker pool
+type CrawlJob struct {
+	ID       primitive.ObjectID `bson:"_id" json:"id"`
+	UserID   string             `bson:"user_id" json:"user_id"`
+	SourceID primitive.ObjectID `bson:"source_id" json:"source_id"`
+	Status   CrawlJobStatus     `bson:"status" json:"status"`
+
+	// Results
+	ArticlesFound int    `bson:"articles_found" json:"articles_found"`
+	ArticlesSaved int    `bson:"articles_saved" json:"articles_saved"`
+	Error         string `bson:"error" json:"error"`
+
+	// Worker bookkeeping
+	Worker string `bson:"worker" json:"-"`
+
+	// Timestamps
+	CreatedAt  time.Time  `bson:"created_at" json:"created_at"`
+	StartedAt  *time.Time `bson:"started_at" json:"started_at"`
+	FinishedAt *time.Time `bson:"finished_at" json:"finished_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CrawlRunStatus string
+
+const (
+	CrawlRunSucceeded   CrawlRunStatus = "succeeded"
+	CrawlRunNotModified CrawlRunStatus = "not_modified"
+	CrawlRunFailed      CrawlRunStatus = "failed"
+)
+
+// CrawlRun records one crawl of a source; old runs expire through a TTL index
+type CrawlRun struct {
+	ID       primitive.ObjectID `bson:"_id" json:"id"`
+	SourceID primitive.ObjectID `bson:"source_id" json:"source_id"`
+	Status   CrawlRunStatus     `bson:"status" json:"status"`
+
+	// Fetch details
+	HTTPStatus      int    `bson:"http_status" json:"http_status"`
+	BytesDownloaded int64  `bson:"bytes_downloaded" json:"bytes_downloaded"`
+	Strategy        string `bson:"strategy" json:"strategy"`   // feed, sitemap, page_feed or html
+	Extractor       string `bson:"extractor" json:"extractor"` // HTML extractor that produced the articles
+
+	// Article counts
+	ArticlesFound         int `bson:"articles_found" json:"articles_found"`
+	ArticlesNew           int `bson:"articles_new" json:"articles_new"`
+	ArticlesDuplicate     int `bson:"articles_duplicate" json:"articles_duplicate"`
+	ArticlesUpdated       int `bson:"articles_updated" json:"articles_updated"`               // known URLs with edited content
+	ArticlesNearDuplicate int `bson:"articles_near_duplicate" json:"articles_near_duplicate"` // saved, but flagged
+	ArticlesRejected      int `bson:"articles_rejected" json:"articles_rejected"`
+
+	Error     string `bson:"error" json:"error"`
+	ErrorCode string `bson:"error_code" json:"error_code"`
+
+	// Timestamps
+	StartedAt  time.Time `bson:"started_at" json:"started_at"`
+	FinishedAt time.Time `bson:"finished_at" json:"finished_at"`
+	DurationMs int64     `bson:"duration_ms" json:"duration_ms"`
+}
@@ -0,0 +1,20 @@
+package models
+
+// ExtractionRules are admin-defined CSS selectors for a source whose pages
+// the built-in extractors get wrong. Selectors other than ItemSelector are
+// evaluated inside each item.
+type ExtractionRules struct {
+	ItemSelector    string `bson:"item_selector" json:"item_selector" validate:"required,max=500"`
+	TitleSelector   string `bson:"title_selector" json:"title_selector" validate:"max=500"`     // defaults to the link text
+	LinkSelector    string `bson:"link_selector" json:"link_selector" validate:"max=500"`       // defaults to the first <a>, or the item itself
+	LinkAttribute   string `bson:"link_attribute" json:"link_attribute" validate:"max=100"`     // defaults to href
+	SummarySelector string `bson:"summary_selector" json:"summary_selector" validate:"max=500"` // optional
+	AuthorSelector  string `bson:"author_selector" json:"author_selector" validate:"max=500"`   // optional
+	DateSelector    string `bson:"date_selector" json:"date_selector" validate:"max=500"`       // optional
+	DateAttribute   string `bson:"date_attribute" json:"date_attribute" validate:"max=100"`     // read the date from this attribute instead of the text
+	DateFormat      string `bson:"date_format" json:"date_format" validate:"max=100"`           // Go time layout; common formats are tried when empty
+
+	// Regular expressions matched against the resolved article URL
+	IncludeURLPattern string `bson:"include_url_pattern" json:"include_url_pattern" validate:"max=500"`
+	ExcludeURLPattern string `bson:"exclude_url_pattern" json:"exclude_url_pattern" validate:"max=500"`
+}
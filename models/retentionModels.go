@@ -0,0 +1,9 @@
+package models
+
+// RetentionPolicy overrides the global retention settings for one source
+// Nil fields fall back to the global settings; 0 turns a limit off
+type RetentionPolicy struct {
+	MaxArticles *int  `bson:"max_articles,omitempty" json:"max_articles,omitempty"` // newest articles kept
+	MaxAgeDays  *int  `bson:"max_age_days,omitempty" json:"max_age_days,omitempty"` // older articles are deleted
+	KeepSaved   *bool `bson:"keep_saved,omitempty" json:"keep_saved,omitempty"`     // never delete starred or saved articles
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SavedArticleKind string
+
+const (
+	SavedArticleStarred SavedArticleKind = "starred"
+	SavedArticleSaved   SavedArticleKind = "saved" // kept for later reading
+)
+
+// SavedArticle marks an article a user starred or saved
+type SavedArticle struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	User_id    string             `bson:"user_id" json:"user_id"`
+	Article_id primitive.ObjectID `bson:"article_id" json:"article_id"`
+	Kind       SavedArticleKind   `bson:"kind" json:"kind"`
+	Created_at time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -11,9 +11,18 @@ type SourceStatus string
 const (
 	SourceStatusActive      SourceStatus = "active"
 	SourceStatusError       SourceStatus = "error"
-	SourceStatusUnreachable SourceStatus = "unreachable"
+	SourceStatusUnreachable SourceStatus = "unreachable" // repeated transient failures; still retried with backoff
+	// robots.txt disallows our crawler; LastError explains which rule applied
+	SourceStatusBlockedByRobots SourceStatus = "blocked_by_robots"
 )
 
+// FetchValidator stores the HTTP cache validators returned for one URL
+type FetchValidator struct {
+	URL          string `bson:"url" json:"url"`
+	ETag         string `bson:"etag" json:"etag"`
+	LastModified string `bson:"last_modified" json:"last_modified"`
+}
+
 type Source struct {
 	ID     primitive.ObjectID `bson:"_id" json:"id"`
 	URL    string             `bson:"url" json:"url" validate:"required,url"`
@@ -24,6 +33,19 @@ type Source struct {
 	LastCrawledAt *time.Time `bson:"last_crawled_at" json:"last_crawled_at"`
 	LastAttemptAt *time.Time `bson:"last_attempt_at" json:"last_attempt_at"`
 	LastError     string     `bson:"last_error" json:"last_error"`
+	LastErrorCode string     `bson:"last_error_code" json:"last_error_code"`
+
+	// Scheduling: the interval adapts to how often the source publishes
+	CrawlIntervalMinutes int        `bson:"crawl_interval_minutes" json:"crawl_interval_minutes"`
+	NextCrawlAt          *time.Time `bson:"next_crawl_at" json:"next_crawl_at"`
+
+	// Retry state for transient failures; reset by a successful crawl
+	ConsecutiveFailures int        `bson:"consecutive_failures" json:"consecutive_failures"`
+	NextRetryAt         *time.Time `bson:"next_retry_at" json:"next_retry_at"`
+
+	// Crawl lease: the instance crawling this source and when its claim lapses
+	LeaseOwner     string     `bson:"lease_owner" json:"-"`
+	LeaseExpiresAt *time.Time `bson:"lease_expires_at" json:"-"`
 
 	// Change detection helpers
 	RSSUrl       string `bson:"rss_url" json:"rss_url"`
@@ -31,6 +53,24 @@ type Source struct {
 	ETag         string `bson:"etag" json:"etag"`
 	LastModified string `bson:"last_modified" json:"last_modified"`
 
+	// Validators for every URL fetched by the last crawl (page, feed, sitemaps)
+	Validators []FetchValidator `bson:"validators" json:"validators"`
+
+	// Admin-defined selectors; when set the page is extracted with them first
+	ExtractionRules *ExtractionRules `bson:"extraction_rules,omitempty" json:"extraction_rules,omitempty"`
+
+	// Fetch each new article's own page (canonical URL and other page metadata)
+	FetchArticlePages bool `bson:"fetch_article_pages" json:"fetch_article_pages"`
+
+	// Readability mode: store each new article's main content (implies fetching article pages)
+	ExtractContent bool `bson:"extract_content" json:"extract_content"`
+
+	// SimHash distance for flagging near-duplicates; nil uses NEAR_DUPLICATE_DISTANCE, -1 turns it off
+	NearDuplicateDistance *int `bson:"near_duplicate_distance,omitempty" json:"near_duplicate_distance,omitempty"`
+
+	// Retention overrides; unset fields use the global policy
+	Retention *RetentionPolicy `bson:"retention,omitempty" json:"retention,omitempty"`
+
 	// Statistics
 	TotalArticles    int `bson:"total_articles" json:"total_articles"`
 	SuccessfulCrawls int `bson:"successful_crawls" json:"successful_crawls"`
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"go-lang-jwt/controllers"
+	"go-lang-jwt/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminRoutes defines routes restricted to ADMIN users
+func AdminRoutes(incomingRoutes *gin.Engine) {
+	adminGroup := incomingRoutes.Group("/api/admin")
+	adminGroup.Use(middleware.Authenticate())
+	{
+		adminGroup.GET("/allowed-hosts", controllers.GetAllowedHosts())
+		adminGroup.POST("/allowed-hosts", controllers.AddAllowedHost())
+		adminGroup.DELETE("/allowed-hosts/:id", controllers.RemoveAllowedHost())
+		adminGroup.POST("/sources/preview", controllers.PreviewSource())
+		adminGroup.PUT("/sources/:id/rules", controllers.SetExtractionRules())
+		adminGroup.DELETE("/sources/:id/rules", controllers.RemoveExtractionRules())
+		adminGroup.PATCH("/sources/:id/settings", controllers.UpdateSourceSettings())
+	}
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"go-lang-jwt/controllers"
+	"go-lang-jwt/middleware"
+	"go-lang-jwt/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ArticleRoutes defines all article-related routes
+func ArticleRoutes(incomingRoutes *gin.Engine) {
+	articleGroup := incomingRoutes.Group("/api/articles")
+	articleGroup.Use(middleware.Authenticate())
+	{
+		articleGroup.GET("/:id", controllers.GetArticle())
+		articleGroup.GET("/:id/revisions", controllers.GetArticleRevisions())
+		articleGroup.PUT("/:id/star", controllers.MarkArticle(models.SavedArticleStarred))
+		articleGroup.DELETE("/:id/star", controllers.UnmarkArticle(models.SavedArticleStarred))
+		articleGroup.PUT("/:id/save", controllers.MarkArticle(models.SavedArticleSaved))
+		articleGroup.DELETE("/:id/save", controllers.UnmarkArticle(models.SavedArticleSaved))
+	}
+
+	savedGroup := incomingRoutes.Group("/api/saved")
+	savedGroup.Use(middleware.Authenticate())
+	{
+		savedGroup.GET("", controllers.GetSavedArticles())
+	}
+}
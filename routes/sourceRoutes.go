@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"go-lang-jwt/controllers"
+	"go-lang-jwt/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SourceRoutes defines all source-related routes
+func SourceRoutes(incomingRoutes *gin.Engine) {
+	sourceGroup := incomingRoutes.Group("/api/sources")
+	sourceGroup.Use(middleware.Authenticate())
+	{
+		sourceGroup.POST("/:id/discover", controllers.DiscoverSource())
+		sourceGroup.GET("/:id/crawls", controllers.GetSourceCrawls())
+	}
+}
@@ -24,6 +24,8 @@ func SubscriptionRoutes(incomingRoutes *gin.Engine) {
 	{
 		crawlGroup.POST("/:id", controllers.CrawlSubscription())
 		crawlGroup.POST("/all", controllers.CrawlAllSubscriptions())
+		crawlGroup.GET("/jobs", controllers.ListCrawlJobs())
+		crawlGroup.GET("/jobs/:id", controllers.GetCrawlJob())
 	}
 
 	// Feed routes
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"go-lang-jwt/database"
+	"go-lang-jwt/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// allowlistRefresh controls how often other replicas pick up allowlist changes
+const allowlistRefresh = time.Minute
+
+var (
+	allowlistHosts    []string
+	allowlistLoadedAt time.Time
+	allowlistMu       sync.Mutex
+)
+
+// ListAllowedHosts returns all admin-approved intranet hosts
+func ListAllowedHosts(ctx context.Context) ([]models.AllowedHost, error) {
+	allowedHostCollection := database.OpenCollection(database.Client, "allowed_hosts")
+
+	opts := options.Find().SetSort(bson.D{{Key: "host", Value: 1}})
+	cursor, err := allowedHostCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query allowed hosts: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	hosts := []models.AllowedHost{}
+	if err = cursor.All(ctx, &hosts); err != nil {
+		return nil, fmt.Errorf("failed to decode allowed hosts: %v", err)
+	}
+
+	return hosts, nil
+}
+
+// AddAllowedHost approves a host; "*.example.internal" matches all subdomains
+func AddAllowedHost(ctx context.Context, adminID string, host string, note string) (*models.AllowedHost, error) {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if host == "" || strings.ContainsAny(host, "/:@ ") {
+		return nil, errors.New("invalid host format")
+	}
+
+	allowedHostCollection := database.OpenCollection(database.Client, "allowed_hosts")
+
+	allowedHost := models.AllowedHost{
+		ID:         primitive.NewObjectID(),
+		Host:       host,
+		Note:       note,
+		Created_by: adminID,
+		Created_at: time.Now(),
+	}
+
+	_, err := allowedHostCollection.InsertOne(ctx, allowedHost)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, errors.New("host already allowed")
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to add allowed host: %v", err)
+	}
+
+	invalidateAllowlist()
+	return &allowedHost, nil
+}
+
+// RemoveAllowedHost revokes an approved host
+func RemoveAllowedHost(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid allowed host ID format")
+	}
+
+	allowedHostCollection := database.OpenCollection(database.Client, "allowed_hosts")
+
+	result, err := allowedHostCollection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return fmt.Errorf("failed to delete allowed host: %v", err)
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("allowed host not found")
+	}
+
+	invalidateAllowlist()
+	return nil
+}
+
+// isHostAllowlisted reports whether an admin approved the host
+func isHostAllowlisted(ctx context.Context, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	for _, allowed := range loadAllowlist(ctx) {
+		if host == allowed {
+			return true
+		}
+		if strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadAllowlist returns the cached allowlist, reloading it when stale
+func loadAllowlist(ctx context.Context) []string {
+	allowlistMu.Lock()
+	defer allowlistMu.Unlock()
+
+	if time.Since(allowlistLoadedAt) < allowlistRefresh {
+		return allowlistHosts
+	}
+
+	hosts, err := ListAllowedHosts(ctx)
+	if err != nil {
+		// Keep the previous list; failing closed only affects intranet hosts
+		log.Printf("Failed to refresh crawler allowlist: %v", err)
+		allowlistLoadedAt = time.Now()
+		return allowlistHosts
+	}
+
+	loaded := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		loaded = append(loaded, host.Host)
+	}
+	allowlistHosts = loaded
+	allowlistLoadedAt = time.Now()
+
+	return allowlistHosts
+}
+
+func invalidateAllowlist() {
+	allowlistMu.Lock()
+	allowlistLoadedAt = time.Time{}
+	allowlistMu.Unlock()
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"go-lang-jwt/helpers"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// articlePageLimit caps how many article pages one crawl fetches
+var articlePageLimit = helpers.GetEnvInt("ARTICLE_PAGE_FETCH_LIMIT", 20)
+
+// articlePage is a fetched and parsed article page
+type articlePage struct {
+	URL   string // final URL after redirects
+	Doc   *goquery.Document
+	Bytes int64 // response body size, for the crawl's download statistics
+}
+
+// fetchArticlePage downloads an article page through the crawler's fetch path
+// (SSRF checks, robots.txt, politeness and size limits all apply)
+func fetchArticlePage(ctx context.Context, articleURL string) (*articlePage, error) {
+	result, err := fetchURL(ctx, articleURL, &fetchOptions{Accept: []string{contentHTML}})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &articlePage{URL: result.URL, Bytes: int64(len(result.Body))}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(result.Body))
+	if err != nil {
+		return page, parseError(fmt.Sprintf("failed to parse article page: %v", err))
+	}
+	doc.Url = documentBase(doc, result.URL)
+	page.Doc = doc
+
+	return page, nil
+}
+
+// enrichFromArticlePage fetches the article's own page and applies what it declares
+// With withContent the page's main content is extracted too
+// Returns the bytes downloaded, which count toward the crawl's statistics
+func enrichFromArticlePage(ctx context.Context, article *ArticleData, withContent bool) (int64, error) {
+	page, err := fetchArticlePage(ctx, article.URL)
+	if err != nil {
+		if page != nil {
+			return page.Bytes, err
+		}
+		return 0, err
+	}
+
+	// The page's canonical URL wins over the link we found it through
+	if canonical := page.canonicalLink(); canonical != "" {
+		if normalized, err := helpers.CanonicalizeURL(canonical); err == nil {
+			article.CanonicalURL = normalized
+		}
+	}
+
+	// JSON-LD, OpenGraph etc. fill in what the listing didn't say
+	pageMetadata(page.Doc).applyTo(article)
+
+	// Last, since boilerplate removal modifies the document
+	if withContent {
+		content, err := extractReadableContent(page.Doc)
+		if err != nil {
+			log.Printf("No content extracted from %s: %v", article.URL, err)
+			return page.Bytes, nil
+		}
+		article.Content = content
+		if article.Summary == "" {
+			article.Summary = truncateSummary(strings.SplitN(content.Text, "\n\n", 2)[0])
+		}
+	}
+
+	return page.Bytes, nil
+}
+
+// canonicalLink returns <link rel="canonical"> or og:url when it stays on the same site
+// Other hosts are ignored so a page can't claim to be another site's article
+func (page *articlePage) canonicalLink() string {
+	pageURL, err := url.Parse(page.URL)
+	if err != nil {
+		return ""
+	}
+
+	candidates := []string{
+		page.Doc.Find(`link[rel~="canonical"]`).First().AttrOr("href", ""),
+		page.Doc.Find(`meta[property="og:url"]`).First().AttrOr("content", ""),
+	}
+
+	for _, candidate := range candidates {
+		link := resolveLink(page.Doc.Url, candidate)
+		if link == "" {
+			continue
+		}
+
+		linkURL, err := url.Parse(link)
+		if err == nil && sameSite(linkURL.Hostname(), pageURL.Hostname()) {
+			return link
+		}
+	}
+	return ""
+}
+
+// sameSite compares hosts ignoring case and a leading "www."
+func sameSite(a string, b string) bool {
+	a = strings.TrimPrefix(strings.ToLower(a), "www.")
+	b = strings.TrimPrefix(strings.ToLower(b), "www.")
+	return a == b
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-lang-jwt/database"
+	"go-lang-jwt/helpers"
+	"go-lang-jwt/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxArticleRevisions caps how many earlier versions are listed
+const maxArticleRevisions = 100
+
+// ArticleVersion is one version of an article with the changes from the version before
+type ArticleVersion struct {
+	Version     int        `json:"version"`
+	Title       string     `json:"title"`
+	Summary     string     `json:"summary"`
+	ContentHash string     `json:"content_hash"`
+	SeenAt      time.Time  `json:"seen_at"`
+	ReplacedAt  *time.Time `json:"replaced_at"` // nil for the current version
+
+	Changes *VersionChanges `json:"changes,omitempty"` // absent for the first version
+}
+
+// VersionChanges holds word diffs of the edited fields
+type VersionChanges struct {
+	Title   []DiffChunk `json:"title"`
+	Summary []DiffChunk `json:"summary"`
+}
+
+// isArticleEdit reports whether a known URL came back with edited content
+// A listing that shows less than what's stored (no summary) only counts when the title changed
+func isArticleEdit(existing *models.Article, article ArticleData) bool {
+	if article.ContentHash == "" || article.ContentHash == existing.Content_hash {
+		return false
+	}
+
+	if article.Summary == "" && existing.Summary != nil && *existing.Summary != "" {
+		return !sameWords(article.Title, existing.Title)
+	}
+	return true
+}
+
+// reviseArticle saves the stored version in article_revisions and applies the edited content
+func reviseArticle(ctx context.Context, existing *models.Article, article ArticleData) error {
+	articleCollection := database.OpenCollection(database.Client, "articles")
+	revisionCollection := database.OpenCollection(database.Client, "article_revisions")
+
+	now := time.Now()
+	version := existing.Version
+	if version < 1 {
+		version = 1 // saved before versions were tracked
+	}
+	seenAt := existing.Discovered_at
+	if existing.Updated_at != nil {
+		seenAt = *existing.Updated_at
+	}
+
+	// Keep the stored summary when the listing no longer shows one
+	summary := existing.Summary
+	if article.Summary != "" {
+		summary = &article.Summary
+	}
+
+	// Step 1: Keep the stored version; the unique (article_id, version) index
+	// rejects it when another crawl is already revising this version
+	revisionID := primitive.NewObjectID()
+	_, err := revisionCollection.InsertOne(ctx, models.ArticleRevision{
+		ID:           revisionID,
+		Article_id:   existing.ID,
+		Version:      version,
+		Title:        existing.Title,
+		Summary:      existing.Summary,
+		Content_hash: existing.Content_hash,
+		Seen_at:      seenAt,
+		Replaced_at:  now,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return errors.New("article changed concurrently")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save article revision: %v", err)
+	}
+
+	// Step 2: Apply the edit, unless the article changed since it was read
+	fingerprint := helpers.GenerateSimHash(article.Title, article.Summary)
+	result, err := articleCollection.UpdateOne(ctx, bson.M{
+		"_id":          existing.ID,
+		"content_hash": existing.Content_hash,
+	}, bson.M{
+		"$set": bson.M{
+			"title":         article.Title,
+			"summary":       summary,
+			"content_hash":  article.ContentHash,
+			"simhash":       int64(fingerprint),
+			"simhash_bands": helpers.SimHashBands(fingerprint),
+			"updated_at":    now,
+			"version":       version + 1,
+		},
+	})
+	if err != nil {
+		discardRevision(ctx, revisionID)
+		return fmt.Errorf("failed to update article: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		discardRevision(ctx, revisionID)
+		return errors.New("article changed concurrently")
+	}
+
+	return nil
+}
+
+// discardRevision removes a revision whose article update didn't happen
+func discardRevision(ctx context.Context, revisionID primitive.ObjectID) {
+	revisionCollection := database.OpenCollection(database.Client, "article_revisions")
+
+	if _, err := revisionCollection.DeleteOne(ctx, bson.M{"_id": revisionID}); err != nil {
+		log.Printf("Failed to discard article revision %s: %v", revisionID.Hex(), err)
+	}
+}
+
+// ListArticleVersions returns every known version of an article, oldest first, the current one last
+// Each version after the first carries a word diff against the one before it
+func ListArticleVersions(ctx context.Context, article *models.Article) ([]ArticleVersion, error) {
+	revisionCollection := database.OpenCollection(database.Client, "article_revisions")
+
+	// The newest revisions when there are more than the cap
+	opts := options.Find().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetLimit(maxArticleRevisions)
+
+	cursor, err := revisionCollection.Find(ctx, bson.M{"article_id": article.ID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch revisions: %v", err)
+	}
+
+	var revisions []models.ArticleRevision
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, fmt.Errorf("failed to decode revisions: %v", err)
+	}
+
+	versions := make([]ArticleVersion, 0, len(revisions)+1)
+	for i := len(revisions) - 1; i >= 0; i-- {
+		revision := revisions[i]
+		replacedAt := revision.Replaced_at
+		versions = append(versions, ArticleVersion{
+			Version:     revision.Version,
+			Title:       revision.Title,
+			Summary:     stringValue(revision.Summary),
+			ContentHash: revision.Content_hash,
+			SeenAt:      revision.Seen_at,
+			ReplacedAt:  &replacedAt,
+		})
+	}
+
+	current := ArticleVersion{
+		Version:     article.Version,
+		Title:       article.Title,
+		Summary:     stringValue(article.Summary),
+		ContentHash: article.Content_hash,
+		SeenAt:      article.Discovered_at,
+	}
+	if current.Version < 1 {
+		current.Version = 1
+	}
+	if article.Updated_at != nil {
+		current.SeenAt = *article.Updated_at
+	}
+	versions = append(versions, current)
+
+	for i := 1; i < len(versions); i++ {
+		versions[i].Changes = &VersionChanges{
+			Title:   wordDiff(versions[i-1].Title, versions[i].Title),
+			Summary: wordDiff(versions[i-1].Summary, versions[i].Summary),
+		}
+	}
+
+	return versions, nil
+}
+
+// sameWords compares two texts ignoring case and whitespace
+func sameWords(a string, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
+
+// stringValue dereferences an optional string
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
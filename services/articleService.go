@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-lang-jwt/database"
+	"go-lang-jwt/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetArticleForUser returns an article, including its content, carried by a source the user is subscribed to
+// Admins can access any article
+func GetArticleForUser(ctx context.Context, userID string, isAdmin bool, articleID string) (*models.Article, error) {
+	objectID, err := primitive.ObjectIDFromHex(articleID)
+	if err != nil {
+		return nil, errors.New("invalid article ID format")
+	}
+
+	articleCollection := database.OpenCollection(database.Client, "articles")
+	subscriptionCollection := database.OpenCollection(database.Client, "subscriptions")
+
+	var article models.Article
+	err = articleCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&article)
+	if err == mongo.ErrNoDocuments {
+		return nil, errors.New("article not found or unauthorized")
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to query article: %v", err)
+	}
+
+	if !isAdmin {
+		count, err := subscriptionCollection.CountDocuments(ctx, bson.M{
+			"user_id":   userID,
+			"source_id": bson.M{"$in": append(article.Source_ids, article.Source_id)},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query subscription: %v", err)
+		}
+		if count == 0 {
+			return nil, errors.New("article not found or unauthorized")
+		}
+	}
+
+	return &article, nil
+}
+
+// linkArticleMention adds sourceID to the sources of the stored article with the same content
+// Reports false when the article already belongs to or mentions the source
+func linkArticleMention(ctx context.Context, sourceID primitive.ObjectID, article ArticleData) (bool, error) {
+	articleCollection := database.OpenCollection(database.Client, "articles")
+
+	mention := models.ArticleMention{
+		Source_id:     sourceID,
+		URL:           article.URL,
+		Discovered_at: time.Now(),
+	}
+
+	// Articles saved before aliases have no source_ids yet; start the list from source_id
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"source_ids": bson.M{"$concatArrays": bson.A{
+				bson.M{"$ifNull": bson.A{"$source_ids", bson.A{"$source_id"}}},
+				bson.A{sourceID},
+			}},
+			"mentions": bson.M{"$concatArrays": bson.A{
+				bson.M{"$ifNull": bson.A{"$mentions", bson.A{}}},
+				bson.A{bson.M{"$literal": mention}},
+			}},
+		}}},
+	}
+
+	result, err := articleCollection.UpdateOne(ctx, bson.M{
+		"content_hash": article.ContentHash,
+		"source_id":    bson.M{"$ne": sourceID},
+		"source_ids":   bson.M{"$ne": sourceID},
+	}, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to link article: %v", err)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
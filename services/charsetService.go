@@ -0,0 +1,88 @@
+package services
+
+import (
+	"bytes"
+	"regexp"
+	"unicode/utf8"
+
+	"go-lang-jwt/helpers"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+)
+
+// fallbackCharsets are tried in order for undeclared pages that aren't valid UTF-8
+// The first that decodes cleanly wins; windows-1252 accepts almost anything so it goes last
+var fallbackCharsets = helpers.GetEnvList("CRAWLER_FALLBACK_CHARSETS", "windows-1252")
+
+var (
+	utf8BOM = []byte("\xef\xbb\xbf")
+
+	// <?xml version="1.0" encoding="windows-1251"?>
+	xmlDeclarationPattern = regexp.MustCompile(`^\s*<\?xml[^>]*?encoding\s*=\s*["']([A-Za-z0-9._:-]+)["']`)
+
+	// <meta charset="shift_jis"> or <meta http-equiv="Content-Type" content="text/html; charset=gbk">
+	metaCharsetPattern = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([A-Za-z0-9._:-]+)`)
+)
+
+// decodeToUTF8 transcodes a response body to UTF-8
+// The charset comes from, in order: a byte order mark, the Content-Type header,
+// the XML declaration, <meta charset>, and finally sniffing the bytes
+// XML declarations are rewritten to say UTF-8 so encoding/xml accepts the result
+func decodeToUTF8(body []byte, contentType string) []byte {
+	// Compressed sitemaps are decoded after gunzipping
+	if len(body) >= 2 && body[0] == 0x1f && body[1] == 0x8b {
+		return body
+	}
+
+	enc, name := detectCharset(body, contentType)
+	if enc != nil && name != "utf-8" {
+		if decoded, err := enc.NewDecoder().Bytes(body); err == nil {
+			body = decoded
+		}
+	}
+
+	body = bytes.TrimPrefix(body, utf8BOM)
+
+	if match := xmlDeclarationPattern.FindSubmatchIndex(body); match != nil {
+		body = append(append(append([]byte{}, body[:match[2]]...), "UTF-8"...), body[match[3]:]...)
+	}
+	return body
+}
+
+// detectCharset returns the body's encoding and its canonical name
+// A nil encoding means the body is taken as is
+func detectCharset(body []byte, contentType string) (encoding.Encoding, string) {
+	// Step 1: Byte order mark and the charset parameter of the header
+	if enc, name, certain := charset.DetermineEncoding(body, contentType); certain {
+		return enc, name
+	}
+
+	// Step 2: Declarations in the document itself (only the start is searched)
+	head := body
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	for _, pattern := range []*regexp.Regexp{xmlDeclarationPattern, metaCharsetPattern} {
+		if match := pattern.FindSubmatch(head); match != nil {
+			if enc, name := charset.Lookup(string(match[1])); enc != nil {
+				return enc, name
+			}
+		}
+	}
+
+	// Step 3: Sniff - valid UTF-8 is kept, anything else goes through the fallbacks
+	if utf8.Valid(body) {
+		return nil, "utf-8"
+	}
+	for _, label := range fallbackCharsets {
+		enc, name := charset.Lookup(label)
+		if enc == nil {
+			continue
+		}
+		if decoded, err := enc.NewDecoder().Bytes(body); err == nil && !bytes.ContainsRune(decoded, utf8.RuneError) {
+			return enc, name
+		}
+	}
+	return nil, ""
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// encodeAs encodes UTF-8 test text in a legacy charset
+func encodeAs(t *testing.T, enc encoding.Encoding, text string) string {
+	encoded, err := enc.NewEncoder().String(text)
+	if err != nil {
+		t.Fatalf("failed to encode %q: %v", text, err)
+	}
+	return encoded
+}
+
+func TestDecodeToUTF8(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		want        string
+	}{
+		{
+			name: "utf-8 kept",
+			body: "<p>Ünïcödé – ok</p>",
+			want: "<p>Ünïcödé – ok</p>",
+		},
+		{
+			name: "utf-8 BOM stripped",
+			body: "\xef\xbb\xbf<p>café</p>",
+			want: "<p>café</p>",
+		},
+		{
+			name:        "header charset",
+			body:        encodeAs(t, charmap.Windows1251, "<p>Привет, мир</p>"),
+			contentType: "text/html; charset=windows-1251",
+			want:        "<p>Привет, мир</p>",
+		},
+		{
+			name: "meta charset",
+			body: encodeAs(t, japanese.ShiftJIS, `<html><head><meta charset="shift_jis"></head><body>日本語のニュース</body></html>`),
+			want: `<html><head><meta charset="shift_jis"></head><body>日本語のニュース</body></html>`,
+		},
+		{
+			name: "http-equiv charset",
+			body: encodeAs(t, charmap.Windows1251, `<meta http-equiv="Content-Type" content="text/html; charset=windows-1251"><p>Новости</p>`),
+			want: `<meta http-equiv="Content-Type" content="text/html; charset=windows-1251"><p>Новости</p>`,
+		},
+		{
+			name:        "xml declaration rewritten",
+			body:        encodeAs(t, charmap.ISO8859_1, `<?xml version="1.0" encoding="ISO-8859-1"?><rss><title>Crème brûlée</title></rss>`),
+			contentType: "application/rss+xml",
+			want:        `<?xml version="1.0" encoding="UTF-8"?><rss><title>Crème brûlée</title></rss>`,
+		},
+		{
+			name:        "header wins over document",
+			body:        encodeAs(t, charmap.Windows1252, `<meta charset="shift_jis"><p>naïve</p>`),
+			contentType: "text/html; charset=windows-1252",
+			want:        `<meta charset="shift_jis"><p>naïve</p>`,
+		},
+		{
+			name: "undeclared falls back to windows-1252",
+			body: encodeAs(t, charmap.Windows1252, "<p>“quoted” café</p>"),
+			want: "<p>“quoted” café</p>",
+		},
+		{
+			name: "gzip left alone",
+			body: "\x1f\x8b\x08\x00rest",
+			want: "\x1f\x8b\x08\x00rest",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := string(decodeToUTF8([]byte(tt.body), tt.contentType)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeToUTF8Fallbacks(t *testing.T) {
+	saved := fallbackCharsets
+	defer func() { fallbackCharsets = saved }()
+	fallbackCharsets = []string{"gbk", "windows-1252"}
+
+	body := encodeAs(t, simplifiedchinese.GBK, "<p>新闻标题</p>")
+	if got := string(decodeToUTF8([]byte(body), "text/html")); got != "<p>新闻标题</p>" {
+		t.Errorf("got %q, want GBK-decoded text", got)
+	}
+}
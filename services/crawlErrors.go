@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Crawl error codes, recorded on the source as last_error_code
+const (
+	CrawlErrorBlockedByRobots = "blocked_by_robots"
+	CrawlErrorBlockedAddress  = "blocked_address"
+
+	// Fetch limit violations
+	CrawlErrorResponseTooLarge       = "response_too_large"
+	CrawlErrorUnsupportedContentType = "unsupported_content_type"
+	CrawlErrorTooManyRedirects       = "too_many_redirects"
+	CrawlErrorConnectTimeout         = "connect_timeout"
+	CrawlErrorTLSTimeout             = "tls_timeout"
+	CrawlErrorHeaderTimeout          = "header_timeout"
+	CrawlErrorTimeout                = "timeout"
+
+	// Network and HTTP failures
+	CrawlErrorDNS         = "dns_error"
+	CrawlErrorNetwork     = "network_error"
+	CrawlErrorRateLimited = "rate_limited"
+	CrawlErrorServerError = "server_error"
+	CrawlErrorNotFound    = "not_found"
+	CrawlErrorHTTPStatus  = "http_status"
+
+	// The response arrived but held nothing we could extract
+	CrawlErrorParseFailed = "parse_failed"
+)
+
+// transientCrawlErrors are worth retrying soon; every other code is permanent
+var transientCrawlErrors = map[string]bool{
+	CrawlErrorConnectTimeout: true,
+	CrawlErrorTLSTimeout:     true,
+	CrawlErrorHeaderTimeout:  true,
+	CrawlErrorTimeout:        true,
+	CrawlErrorDNS:            true,
+	CrawlErrorNetwork:        true,
+	CrawlErrorRateLimited:    true,
+	CrawlErrorServerError:    true,
+}
+
+// CrawlError is a crawl failure with a machine-readable code
+type CrawlError struct {
+	Code       string
+	Message    string
+	StatusCode int // HTTP status, when the failure was a response
+}
+
+func (e *CrawlError) Error() string {
+	return e.Message
+}
+
+// crawlErrorCode returns the code of a CrawlError anywhere in the chain
+func crawlErrorCode(err error) string {
+	var crawlErr *CrawlError
+	if errors.As(err, &crawlErr) {
+		return crawlErr.Code
+	}
+	return ""
+}
+
+// isTransientCrawlError reports whether a failure may go away on its own
+// Uncoded errors (unexpected I/O failures) are treated as transient
+func isTransientCrawlError(err error) bool {
+	code := crawlErrorCode(err)
+	if code == "" {
+		return !errors.Is(err, context.Canceled)
+	}
+	return transientCrawlErrors[code]
+}
+
+// httpStatusError maps an unexpected response status to a crawl error
+func httpStatusError(statusCode int) error {
+	code := CrawlErrorHTTPStatus
+	switch {
+	case statusCode == http.StatusNotFound || statusCode == http.StatusGone:
+		code = CrawlErrorNotFound
+	case statusCode == http.StatusTooManyRequests:
+		code = CrawlErrorRateLimited
+	case statusCode >= 500:
+		code = CrawlErrorServerError
+	}
+
+	return &CrawlError{
+		Code:       code,
+		Message:    fmt.Sprintf("bad status code: %d", statusCode),
+		StatusCode: statusCode,
+	}
+}
+
+// parseError marks content that could not be turned into articles
+func parseError(message string) error {
+	return &CrawlError{Code: CrawlErrorParseFailed, Message: message}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"go-lang-jwt/database"
+	"go-lang-jwt/helpers"
+	"go-lang-jwt/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Worker pool settings
+var (
+	crawlWorkers      = helpers.GetEnvInt("CRAWLER_MAX_WORKERS", 8)
+	crawlJobTimeout   = helpers.GetEnvDuration("CRAWLER_JOB_TIMEOUT", 5*time.Minute)
+	crawlJobPollEvery = helpers.GetEnvDuration("CRAWLER_JOB_POLL_INTERVAL", 2*time.Second)
+)
+
+// instanceID identifies this API replica as a job worker
+var instanceID = newInstanceID()
+
+// jobWakeup nudges idle workers when a job is queued in this process
+var jobWakeup = make(chan struct{}, 1)
+
+func newInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), primitive.NewObjectID().Hex()[18:])
+}
+
+// EnqueueCrawlJob persists a queued crawl job for a source
+func EnqueueCrawlJob(ctx context.Context, userID string, sourceID primitive.ObjectID) (*models.CrawlJob, error) {
+	jobCollection := database.OpenCollection(database.Client, "crawl_jobs")
+
+	job := models.CrawlJob{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		SourceID:  sourceID,
+		Status:    models.CrawlJobQueued,
+		CreatedAt: time.Now(),
+	}
+
+	_, err := jobCollection.InsertOne(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue crawl job: %v", err)
+	}
+
+	// Wake an idle worker without blocking
+	select {
+	case jobWakeup <- struct{}{}:
+	default:
+	}
+
+	return &job, nil
+}
+
+// GetCrawlJob returns a job owned by the user (admins can read any job)
+func GetCrawlJob(ctx context.Context, userID string, isAdmin bool, jobID string) (*models.CrawlJob, error) {
+	objectID, err := primitive.ObjectIDFromHex(jobID)
+	if err != nil {
+		return nil, errors.New("invalid job ID format")
+	}
+
+	jobCollection := database.OpenCollection(database.Client, "crawl_jobs")
+
+	filter := bson.M{"_id": objectID}
+	if !isAdmin {
+		filter["user_id"] = userID
+	}
+
+	var job models.CrawlJob
+	err = jobCollection.FindOne(ctx, filter).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return nil, errors.New("job not found")
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to query job: %v", err)
+	}
+
+	return &job, nil
+}
+
+// ListCrawlJobs returns a user's most recent jobs, newest first
+func ListCrawlJobs(ctx context.Context, userID string, limit int) ([]models.CrawlJob, error) {
+	jobCollection := database.OpenCollection(database.Client, "crawl_jobs")
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := jobCollection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	jobs := []models.CrawlJob{}
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to decode jobs: %v", err)
+	}
+
+	return jobs, nil
+}
+
+// StartCrawlWorkers launches the worker pool that processes queued crawl jobs
+// Every replica runs its own pool; jobs are claimed atomically in MongoDB
+func StartCrawlWorkers(ctx context.Context) {
+	log.Printf("Starting %d crawl workers (%s)", crawlWorkers, instanceID)
+
+	for i := 0; i < crawlWorkers; i++ {
+		go runCrawlWorker(ctx)
+	}
+}
+
+func runCrawlWorker(ctx context.Context) {
+	for {
+		job, err := claimCrawlJob(ctx)
+		if err != nil {
+			log.Printf("Failed to claim crawl job: %v", err)
+		}
+
+		if job == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-jobWakeup:
+			case <-time.After(crawlJobPollEvery):
+			}
+			continue
+		}
+
+		runCrawlJob(ctx, job)
+	}
+}
+
+// claimCrawlJob atomically moves the oldest queued job to running
+// Jobs left running by a crashed worker are picked up again after twice the job timeout
+func claimCrawlJob(ctx context.Context) (*models.CrawlJob, error) {
+	jobCollection := database.OpenCollection(database.Client, "crawl_jobs")
+
+	now := time.Now()
+	filter := bson.M{
+		"$or": []bson.M{
+			{"status": models.CrawlJobQueued},
+			{"status": models.CrawlJobRunning, "started_at": bson.M{"$lt": now.Add(-2 * crawlJobTimeout)}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     models.CrawlJobRunning,
+			"started_at": now,
+			"worker":     instanceID,
+		},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var job models.CrawlJob
+	err := jobCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// runCrawlJob crawls the job's source and records the outcome
+func runCrawlJob(ctx context.Context, job *models.CrawlJob) {
+	crawlCtx, cancel := context.WithTimeout(ctx, crawlJobTimeout)
+	defer cancel()
+
+	stats, err := CrawlSource(crawlCtx, job.SourceID)
+
+	finished := time.Now()
+	update := bson.M{
+		"status":      models.CrawlJobSucceeded,
+		"finished_at": finished,
+	}
+	if stats != nil {
+		update["articles_found"] = stats.ArticlesFound
+		update["articles_saved"] = stats.ArticlesSaved
+	}
+	if errors.Is(err, ErrCrawlInProgress) {
+		update["status"] = models.CrawlJobSkipped
+		update["error"] = err.Error()
+	} else if err != nil {
+		log.Printf("Crawl job %s failed: %v", job.ID.Hex(), err)
+		update["status"] = models.CrawlJobFailed
+		update["error"] = err.Error()
+	}
+
+	// Record the outcome even if the crawl context expired
+	saveCtx, saveCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer saveCancel()
+
+	jobCollection := database.OpenCollection(database.Client, "crawl_jobs")
+	_, updateErr := jobCollection.UpdateOne(saveCtx, bson.M{"_id": job.ID, "worker": instanceID}, bson.M{"$set": update})
+	if updateErr != nil {
+		log.Printf("Failed to record crawl job %s: %v", job.ID.Hex(), updateErr)
+	}
+}
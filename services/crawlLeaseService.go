@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"go-lang-jwt/database"
+	"go-lang-jwt/helpers"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// crawlLeaseTTL is how long a lease survives without renewal (e.g. after a crash)
+var crawlLeaseTTL = helpers.GetEnvDuration("CRAWL_LEASE_TTL", 2*time.Minute)
+
+// ErrCrawlInProgress is returned when another replica holds the source's crawl lease
+var ErrCrawlInProgress = errors.New("crawl already in progress on another instance")
+
+// inflightCrawl is a crawl running in this process that other callers can join
+type inflightCrawl struct {
+	done  chan struct{}
+	stats *CrawlStats
+	err   error
+}
+
+var (
+	inflightCrawls   = map[primitive.ObjectID]*inflightCrawl{}
+	inflightCrawlsMu sync.Mutex
+)
+
+// CrawlSource crawls a single source and saves articles
+// Concurrent calls for the same source in this process share one crawl, and a
+// MongoDB lease keeps other replicas from crawling the source at the same time
+func CrawlSource(ctx context.Context, sourceID primitive.ObjectID) (*CrawlStats, error) {
+	inflightCrawlsMu.Lock()
+	if call, ok := inflightCrawls[sourceID]; ok {
+		inflightCrawlsMu.Unlock()
+
+		// Join the crawl that is already running
+		select {
+		case <-call.done:
+			return call.stats, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	call := &inflightCrawl{done: make(chan struct{})}
+	inflightCrawls[sourceID] = call
+	inflightCrawlsMu.Unlock()
+
+	call.stats, call.err = crawlWithLease(ctx, sourceID)
+
+	inflightCrawlsMu.Lock()
+	delete(inflightCrawls, sourceID)
+	inflightCrawlsMu.Unlock()
+	close(call.done)
+
+	return call.stats, call.err
+}
+
+// crawlWithLease holds the source's lease for the duration of the crawl
+func crawlWithLease(ctx context.Context, sourceID primitive.ObjectID) (*CrawlStats, error) {
+	owner := instanceID + "/" + primitive.NewObjectID().Hex()
+
+	acquired, err := acquireCrawlLease(ctx, sourceID, owner)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrCrawlInProgress
+	}
+	defer releaseCrawlLease(sourceID, owner)
+
+	// Keep the lease alive while the crawl runs; losing it stops the crawl
+	crawlCtx, cancelCrawl := context.WithCancel(ctx)
+	defer cancelCrawl()
+	go renewCrawlLease(crawlCtx, sourceID, owner, cancelCrawl)
+
+	return crawlSource(crawlCtx, sourceID)
+}
+
+// acquireCrawlLease atomically takes the lease if it is free or expired
+func acquireCrawlLease(ctx context.Context, sourceID primitive.ObjectID, owner string) (bool, error) {
+	sourceCollection := database.OpenCollection(database.Client, "sources")
+
+	now := time.Now()
+	result, err := sourceCollection.UpdateOne(ctx, bson.M{
+		"_id": sourceID,
+		"$or": []bson.M{
+			{"lease_expires_at": nil},
+			{"lease_expires_at": bson.M{"$lt": now}},
+		},
+	}, bson.M{
+		"$set": bson.M{
+			"lease_owner":      owner,
+			"lease_expires_at": now.Add(crawlLeaseTTL),
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if result.MatchedCount == 1 {
+		return true, nil
+	}
+
+	// Either the source doesn't exist or someone else holds the lease
+	count, err := sourceCollection.CountDocuments(ctx, bson.M{"_id": sourceID})
+	if err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, errors.New("source not found")
+	}
+	return false, nil
+}
+
+// renewCrawlLease extends the lease every third of its TTL until ctx is done
+// When the lease was lost (it expired and another instance took it) the crawl is canceled
+func renewCrawlLease(ctx context.Context, sourceID primitive.ObjectID, owner string, cancelCrawl context.CancelFunc) {
+	ticker := time.NewTicker(crawlLeaseTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		owned, err := extendCrawlLease(ctx, sourceID, owner)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to renew crawl lease for %s: %v", sourceID.Hex(), err)
+			}
+			continue
+		}
+		if !owned {
+			log.Printf("Lost crawl lease for %s to another instance, stopping the crawl", sourceID.Hex())
+			cancelCrawl()
+			return
+		}
+	}
+}
+
+// extendCrawlLease pushes the lease's expiry out; false means owner no longer holds it
+// A variable so tests can simulate a takeover
+var extendCrawlLease = func(ctx context.Context, sourceID primitive.ObjectID, owner string) (bool, error) {
+	sourceCollection := database.OpenCollection(database.Client, "sources")
+
+	result, err := sourceCollection.UpdateOne(ctx, bson.M{"_id": sourceID, "lease_owner": owner}, bson.M{
+		"$set": bson.M{"lease_expires_at": time.Now().Add(crawlLeaseTTL)},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// releaseCrawlLease frees the lease if we still own it
+func releaseCrawlLease(sourceID primitive.ObjectID, owner string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	sourceCollection := database.OpenCollection(database.Client, "sources")
+	_, err := sourceCollection.UpdateOne(ctx, bson.M{"_id": sourceID, "lease_owner": owner}, bson.M{
+		"$set": bson.M{
+			"lease_owner":      "",
+			"lease_expires_at": nil,
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to release crawl lease for %s: %v", sourceID.Hex(), err)
+	}
+}
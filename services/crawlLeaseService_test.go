@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// withLeaseRenewal makes leases renew every few milliseconds against a fake store
+func withLeaseRenewal(t *testing.T, extend func(renewals int32) (bool, error)) *int32 {
+	savedTTL, savedExtend := crawlLeaseTTL, extendCrawlLease
+	t.Cleanup(func() { crawlLeaseTTL, extendCrawlLease = savedTTL, savedExtend })
+
+	var renewals int32
+	crawlLeaseTTL = 30 * time.Millisecond
+	extendCrawlLease = func(ctx context.Context, sourceID primitive.ObjectID, owner string) (bool, error) {
+		return extend(atomic.AddInt32(&renewals, 1))
+	}
+	return &renewals
+}
+
+func TestRenewCrawlLeaseCancelsCrawlOnTakeover(t *testing.T) {
+	// The lease is renewed twice, then another instance owns it
+	renewals := withLeaseRenewal(t, func(renewals int32) (bool, error) {
+		return renewals < 3, nil
+	})
+
+	crawlCtx, cancelCrawl := context.WithCancel(context.Background())
+	defer cancelCrawl()
+
+	done := make(chan struct{})
+	go func() {
+		renewCrawlLease(crawlCtx, primitive.NewObjectID(), "instance-a/1", cancelCrawl)
+		close(done)
+	}()
+
+	select {
+	case <-crawlCtx.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("crawl was not canceled after the lease was taken over")
+	}
+	<-done
+
+	if got := atomic.LoadInt32(renewals); got != 3 {
+		t.Errorf("renewal attempts = %d, want 3 (stop at the first lost renewal)", got)
+	}
+}
+
+func TestRenewCrawlLeaseKeepsCrawlWhileOwned(t *testing.T) {
+	// Errors are transient: the lease may still be ours
+	renewals := withLeaseRenewal(t, func(renewals int32) (bool, error) {
+		if renewals == 2 {
+			return false, context.DeadlineExceeded
+		}
+		return true, nil
+	})
+
+	ctx, stop := context.WithCancel(context.Background())
+	canceled := make(chan struct{}, 1)
+
+	done := make(chan struct{})
+	go func() {
+		renewCrawlLease(ctx, primitive.NewObjectID(), "instance-a/1", func() { canceled <- struct{}{} })
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(renewals) < 4 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	stop()
+	<-done
+
+	if got := atomic.LoadInt32(renewals); got < 4 {
+		t.Fatalf("renewal attempts = %d, want at least 4", got)
+	}
+	select {
+	case <-canceled:
+		t.Error("crawl was canceled while the lease was still owned")
+	default:
+	}
+}
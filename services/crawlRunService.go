@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go-lang-jwt/database"
+	"go-lang-jwt/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// recordCrawlRun saves the history entry for one crawl
+// Failures are only logged; losing a history entry must not fail the crawl
+func recordCrawlRun(run models.CrawlRun) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	run.ID = primitive.NewObjectID()
+	run.FinishedAt = time.Now()
+	run.DurationMs = run.FinishedAt.Sub(run.StartedAt).Milliseconds()
+
+	runCollection := database.OpenCollection(database.Client, "crawl_runs")
+	if _, err := runCollection.InsertOne(ctx, run); err != nil {
+		log.Printf("Failed to record crawl run for source %s: %v", run.SourceID.Hex(), err)
+	}
+}
+
+// ListCrawlRuns returns a page of a source's crawl history, newest first
+func ListCrawlRuns(ctx context.Context, sourceID primitive.ObjectID, page int, limit int) ([]models.CrawlRun, int64, error) {
+	runCollection := database.OpenCollection(database.Client, "crawl_runs")
+
+	filter := bson.M{"source_id": sourceID}
+
+	// Count total runs
+	totalCount, err := runCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count crawl runs: %v", err)
+	}
+
+	// Calculate pagination
+	skip := (page - 1) * limit
+	opts := options.Find().
+		SetSort(bson.D{{Key: "started_at", Value: -1}}).
+		SetSkip(int64(skip)).
+		SetLimit(int64(limit))
+
+	cursor, err := runCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch crawl runs: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	runs := []models.CrawlRun{}
+	if err = cursor.All(ctx, &runs); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode crawl runs: %v", err)
+	}
+
+	return runs, totalCount, nil
+}
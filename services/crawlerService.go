@@ -2,20 +2,33 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"go-lang-jwt/database"
+	"go-lang-jwt/helpers"
 	"go-lang-jwt/models"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// CrawlSource crawls a single source and saves articles
-func CrawlSource(ctx context.Context, sourceID primitive.ObjectID) error {
+// CrawlStats summarizes one crawl of a source
+type CrawlStats struct {
+	ArticlesFound         int `json:"articles_found"`
+	ArticlesSaved         int `json:"articles_saved"`
+	ArticlesDuplicate     int `json:"articles_duplicate"`
+	ArticlesUpdated       int `json:"articles_updated"`
+	ArticlesNearDuplicate int `json:"articles_near_duplicate"` // saved, flagged as near-duplicates
+	ArticlesRejected      int `json:"articles_rejected"`
+}
+
+// crawlSource crawls a single source and saves articles; callers hold the crawl lease
+func crawlSource(ctx context.Context, sourceID primitive.ObjectID) (*CrawlStats, error) {
 	sourceCollection := database.OpenCollection(database.Client, "sources")
 	articleCollection := database.OpenCollection(database.Client, "articles")
 
@@ -23,57 +36,120 @@ func CrawlSource(ctx context.Context, sourceID primitive.ObjectID) error {
 	var source models.Source
 	err := sourceCollection.FindOne(ctx, bson.M{"_id": sourceID}).Decode(&source)
 	if err != nil {
-		return fmt.Errorf("source not found: %v", err)
+		return nil, fmt.Errorf("source not found: %v", err)
 	}
 
 	// Step 2: Update last attempt timestamp
 	now := time.Now()
-	sourceCollection.UpdateOne(ctx, bson.M{"_id": sourceID}, bson.M{
+	updateSourceState(sourceID, bson.M{
 		"$set": bson.M{"last_attempt_at": now},
 	})
 
+	// Every crawl leaves an entry in the crawl history
+	run := models.CrawlRun{
+		SourceID:  sourceID,
+		Status:    models.CrawlRunSucceeded,
+		StartedAt: now,
+	}
+	defer func() { recordCrawlRun(run) }()
+
 	// Step 3: Extract articles from URL
 	log.Printf("Crawling source: %s (%s)", source.Name, source.URL)
-	articles, err := ExtractArticles(ctx, source)
+	result, err := ExtractArticles(ctx, source)
+	run.HTTPStatus = result.HTTPStatus
+	run.BytesDownloaded = result.BytesDownloaded
 	if err != nil {
-		// Update source with error
-		sourceCollection.UpdateOne(ctx, bson.M{"_id": sourceID}, bson.M{
-			"$set": bson.M{
-				"status":     models.SourceStatusError,
-				"last_error": err.Error(),
-				"updated_at": time.Now(),
-			},
-			"$inc": bson.M{"failed_crawls": 1},
-		})
-		return fmt.Errorf("failed to extract articles: %v", err)
+		run.Status = models.CrawlRunFailed
+		run.Error = err.Error()
+		run.ErrorCode = crawlErrorCode(err)
+
+		// A canceled crawl (lost lease, shutdown) says nothing about the source,
+		// and another instance may be crawling it now
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			recordCrawlFailure(source, err)
+		}
+		return nil, fmt.Errorf("failed to extract articles: %w", err)
+	}
+
+	run.Strategy = result.Strategy
+	run.Extractor = result.Extractor
+	if result.NotModified {
+		run.Status = models.CrawlRunNotModified
+		log.Printf("Source not modified since last crawl: %s", source.Name)
 	}
 
+	articles := result.Articles
 	log.Printf("Found %d articles from %s", len(articles), source.Name)
 
 	// Step 4: Save articles (deduplicate)
+	stats := &CrawlStats{ArticlesFound: len(articles)}
 	savedCount := 0
+	pagesFetched := 0
 	for _, articleData := range articles {
-		// Check if article already exists (by source_id + url)
-		existingCount, _ := articleCollection.CountDocuments(ctx, bson.M{
-			"source_id": sourceID,
-			"url":       articleData.URL,
-		})
+		// Dedupe on the canonical URL (tracking parameters, fragments etc. removed)
+		canonicalURL, err := helpers.CanonicalizeURL(articleData.URL)
+		if err != nil {
+			log.Printf("Rejecting article with invalid URL %q: %v", articleData.URL, err)
+			stats.ArticlesRejected++
+			continue
+		}
+		articleData.CanonicalURL = canonicalURL
 
-		if existingCount > 0 {
-			log.Printf("Skipping duplicate URL: %s", articleData.URL)
+		// Known articles are compared as the listing shows them; new ones are hashed again after enrichment
+		articleData.ContentHash = contentHashOf(articleData)
+
+		// Check if article already exists (by source_id + canonical or raw url);
+		// a known URL with different content means the publisher edited it
+		if existing := findExistingArticle(ctx, sourceID, articleData); existing != nil {
+			countKnownArticle(ctx, stats, existing, articleData)
 			continue
 		}
 
+		// Optionally read the article's own page for its metadata; its canonical URL may reveal a duplicate
+		if (source.FetchArticlePages || source.ExtractContent) && pagesFetched < articlePageLimit {
+			pagesFetched++
+			downloaded, err := enrichFromArticlePage(ctx, &articleData, source.ExtractContent)
+			run.BytesDownloaded += downloaded
+			articleData.ContentHash = contentHashOf(articleData)
+			if err != nil {
+				log.Printf("Failed to fetch article page %s: %v", articleData.URL, err)
+			} else if articleData.CanonicalURL != canonicalURL {
+				if existing := findExistingArticle(ctx, sourceID, articleData); existing != nil {
+					countKnownArticle(ctx, stats, existing, articleData)
+					continue
+				}
+			}
+		}
+
 		// Check if content hash already exists (duplicate content)
 		hashCount, _ := articleCollection.CountDocuments(ctx, bson.M{
 			"content_hash": articleData.ContentHash,
 		})
 
 		if hashCount > 0 {
-			log.Printf("Skipping duplicate content: %s", articleData.Title)
+			// Another source's copy is linked, so our subscribers still see the story
+			linked, err := linkArticleMention(ctx, sourceID, articleData)
+			if err != nil {
+				log.Printf("Failed to link duplicate content %s: %v", articleData.URL, err)
+			} else if linked {
+				log.Printf("Linked duplicate content from %s: %s", source.Name, articleData.Title)
+			} else {
+				log.Printf("Skipping duplicate content: %s", articleData.Title)
+			}
+			stats.ArticlesDuplicate++
 			continue
 		}
 
+		// Similar (not identical) stories are saved but point at the original
+		fingerprint := helpers.GenerateSimHash(articleData.Title, articleData.Summary)
+		nearDuplicateOf, err := findNearDuplicate(ctx, fingerprint, nearDuplicateDistanceOf(source))
+		if err != nil {
+			log.Printf("Near-duplicate lookup failed for %s: %v", articleData.URL, err)
+		}
+		if nearDuplicateOf != nil {
+			log.Printf("Near-duplicate of %s: %s", nearDuplicateOf.Hex(), articleData.Title)
+		}
+
 		// Create article document
 		var summary *string
 		if articleData.Summary != "" {
@@ -85,122 +161,217 @@ func CrawlSource(ctx context.Context, sourceID primitive.ObjectID) error {
 			author = &articleData.Author
 		}
 
+		var guid *string
+		if articleData.GUID != "" {
+			guid = &articleData.GUID
+		}
+
+		var image *string
+		if articleData.Image != "" {
+			image = &articleData.Image
+		}
+
+		var section *string
+		if articleData.Section != "" {
+			section = &articleData.Section
+		}
+
 		article := models.Article{
 			ID:            primitive.NewObjectID(),
 			Source_id:     sourceID,
 			Title:         articleData.Title,
 			URL:           articleData.URL,
+			Canonical_url: articleData.CanonicalURL,
+			Guid:          guid,
 			Content_hash:  articleData.ContentHash,
 			Summary:       summary,
 			Published_at:  articleData.PublishedAt,
 			Discovered_at: time.Now(),
+			Version:       1,
 			Author:        author,
+			Image_url:     image,
+			Section:       section,
+
+			Simhash:           int64(fingerprint),
+			Simhash_bands:     helpers.SimHashBands(fingerprint),
+			Near_duplicate_of: nearDuplicateOf,
+
+			Source_ids: []primitive.ObjectID{sourceID},
+			Mentions:   []models.ArticleMention{},
+		}
+		if content := articleData.Content; content != nil {
+			article.Content_html = &content.HTML
+			article.Content_text = &content.Text
+			article.Word_count = content.WordCount
+			article.Reading_time_minutes = content.ReadingMinutes
 		}
 
-		_, err := articleCollection.InsertOne(ctx, article)
+		_, err = articleCollection.InsertOne(ctx, article)
+		if mongo.IsDuplicateKeyError(err) {
+			stats.ArticlesDuplicate++
+			continue
+		}
 		if err != nil {
 			log.Printf("Failed to save article: %v", err)
+			stats.ArticlesRejected++
 			continue
 		}
 
 		savedCount++
+		if nearDuplicateOf != nil {
+			stats.ArticlesNearDuplicate++
+		}
 	}
+	stats.ArticlesSaved = savedCount
+
+	run.ArticlesFound = stats.ArticlesFound
+	run.ArticlesNew = stats.ArticlesSaved
+	run.ArticlesDuplicate = stats.ArticlesDuplicate
+	run.ArticlesUpdated = stats.ArticlesUpdated
+	run.ArticlesNearDuplicate = stats.ArticlesNearDuplicate
+	run.ArticlesRejected = stats.ArticlesRejected
 
 	log.Printf("Saved %d new articles from %s", savedCount, source.Name)
 
-	// Step 5: Cleanup old articles (keep only 50 newest per source)
-	err = cleanupOldArticles(ctx, sourceID)
-	if err != nil {
-		log.Printf("Warning: Failed to cleanup old articles: %v", err)
+	if errors.Is(ctx.Err(), context.Canceled) {
+		run.Status = models.CrawlRunFailed
+		run.Error = "crawl canceled"
+		return nil, fmt.Errorf("crawl canceled: %w", ctx.Err())
 	}
 
-	// Step 6: Update source with success
-	sourceCollection.UpdateOne(ctx, bson.M{"_id": sourceID}, bson.M{
+	// Step 5: Update source with success, the validators for the next conditional crawl
+	// and the next scheduled crawl (sooner when the source published something new)
+	pageValidator := validatorFor(result.Validators, source.URL)
+	interval := nextCrawlInterval(crawlIntervalOf(source), savedCount, false)
+	err = updateSourceState(sourceID, bson.M{
 		"$set": bson.M{
-			"status":          models.SourceStatusActive,
-			"last_crawled_at": now,
-			"last_error":      "",
-			"updated_at":      time.Now(),
+			"status":                 models.SourceStatusActive,
+			"last_crawled_at":        now,
+			"last_error":             "",
+			"last_error_code":        "",
+			"consecutive_failures":   0,
+			"next_retry_at":          nil,
+			"validators":             result.Validators,
+			"etag":                   pageValidator.ETag,
+			"last_modified":          pageValidator.LastModified,
+			"crawl_interval_minutes": int(interval / time.Minute),
+			"next_crawl_at":          jitter(time.Now().Add(interval)),
+			"updated_at":             time.Now(),
 		},
 		"$inc": bson.M{
 			"successful_crawls": 1,
 			"total_articles":    savedCount,
 		},
 	})
-
-	return nil
-}
-
-// cleanupOldArticles keeps only the 50 newest articles per source
-func cleanupOldArticles(ctx context.Context, sourceID primitive.ObjectID) error {
-	articleCollection := database.OpenCollection(database.Client, "articles")
-
-	// Count total articles for this source
-	count, err := articleCollection.CountDocuments(ctx, bson.M{"source_id": sourceID})
 	if err != nil {
-		return err
+		log.Printf("Failed to record crawl success for %s: %v", source.URL, err)
 	}
 
-	// If less than 50, no cleanup needed
-	if count <= 50 {
-		return nil
+	return stats, nil
+}
+
+// contentHashOf hashes what is stored for the article: its title and summary
+// Titles derived from the URL are hashed with it so generic slugs don't collide
+func contentHashOf(article ArticleData) string {
+	if article.titleFromURL {
+		return helpers.GenerateContentHash(article.Title, article.URL)
 	}
+	return helpers.GenerateContentHash(article.Title, article.Summary)
+}
 
-	// Find articles to delete (oldest ones)
-	opts := options.Find().
-		SetSort(bson.D{{Key: "discovered_at", Value: -1}}).
-		SetSkip(50)
+// findExistingArticle returns the source's article stored under the canonical or raw URL, or nil
+// Articles saved before canonical URLs existed only match on url
+func findExistingArticle(ctx context.Context, sourceID primitive.ObjectID, article ArticleData) *models.Article {
+	articleCollection := database.OpenCollection(database.Client, "articles")
 
-	cursor, err := articleCollection.Find(ctx, bson.M{"source_id": sourceID}, opts)
+	var existing models.Article
+	err := articleCollection.FindOne(ctx, bson.M{
+		"source_id": sourceID,
+		"$or": []bson.M{
+			{"canonical_url": article.CanonicalURL},
+			{"url": article.URL},
+		},
+	}, options.FindOne().SetProjection(bson.M{"content_html": 0, "content_text": 0})).Decode(&existing)
 	if err != nil {
-		return err
+		return nil
 	}
-	defer cursor.Close(ctx)
+	return &existing
+}
 
-	var articlesToDelete []primitive.ObjectID
-	for cursor.Next(ctx) {
-		var article models.Article
-		if err := cursor.Decode(&article); err != nil {
-			continue
-		}
-		articlesToDelete = append(articlesToDelete, article.ID)
+// countKnownArticle records a revision when a known article's content changed, else counts a duplicate
+func countKnownArticle(ctx context.Context, stats *CrawlStats, existing *models.Article, article ArticleData) {
+	if !isArticleEdit(existing, article) {
+		log.Printf("Skipping duplicate URL: %s", article.URL)
+		stats.ArticlesDuplicate++
+		return
 	}
 
-	// Delete old articles
-	if len(articlesToDelete) > 0 {
-		_, err = articleCollection.DeleteMany(ctx, bson.M{
-			"_id": bson.M{"$in": articlesToDelete},
-		})
-		if err != nil {
-			return err
-		}
-		log.Printf("Deleted %d old articles from source", len(articlesToDelete))
+	if err := reviseArticle(ctx, existing, article); err != nil {
+		log.Printf("Failed to update edited article %s: %v", article.URL, err)
+		stats.ArticlesDuplicate++
+		return
 	}
 
-	return nil
+	log.Printf("Updated edited article: %s", article.Title)
+	stats.ArticlesUpdated++
 }
 
-// CrawlUserSources crawls all sources for a specific user
-func CrawlUserSources(ctx context.Context, userID string) (int, int, error) {
-	// Get user's subscriptions
-	subscriptions, err := ListSubscriptions(ctx, userID)
-	if err != nil {
-		return 0, 0, err
-	}
+// updateSourceState writes a crawl's outcome to the source
+// It uses its own context: the crawl's may have expired, and a timeout is an outcome too
+func updateSourceState(sourceID primitive.ObjectID, update bson.M) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	successCount := 0
-	failCount := 0
+	sourceCollection := database.OpenCollection(database.Client, "sources")
+	_, err := sourceCollection.UpdateOne(ctx, bson.M{"_id": sourceID}, update)
+	return err
+}
 
-	// Crawl each subscribed source
-	for _, sub := range subscriptions {
-		err := CrawlSource(ctx, sub.Source.ID)
-		if err != nil {
-			log.Printf("Failed to crawl source %s: %v", sub.Source.Name, err)
-			failCount++
-		} else {
-			successCount++
+// recordCrawlFailure stores the error and decides when the source is tried again
+// Transient failures are retried with exponential backoff and mark the source unreachable
+// after unreachableAfter attempts in a row; permanent ones wait for the next scheduled crawl
+func recordCrawlFailure(source models.Source, crawlErr error) {
+	code := crawlErrorCode(crawlErr)
+	failures := source.ConsecutiveFailures + 1
+	now := time.Now()
+
+	// Back off: failing sources are crawled less often
+	interval := nextCrawlInterval(crawlIntervalOf(source), 0, true)
+	nextCrawl := jitter(now.Add(interval))
+
+	status := models.SourceStatusError
+	var nextRetry *time.Time
+	switch {
+	case code == CrawlErrorBlockedByRobots:
+		status = models.SourceStatusBlockedByRobots
+	case isTransientCrawlError(crawlErr):
+		retryAt := now.Add(retryDelay(failures))
+		nextRetry = &retryAt
+		if retryAt.Before(nextCrawl) {
+			nextCrawl = retryAt
+		}
+		if failures >= unreachableAfter {
+			status = models.SourceStatusUnreachable
+		} else if source.Status == models.SourceStatusActive || source.Status == "" {
+			// A single hiccup doesn't mark a healthy source as broken
+			status = models.SourceStatusActive
 		}
 	}
 
-	return successCount, failCount, nil
+	err := updateSourceState(source.ID, bson.M{
+		"$set": bson.M{
+			"status":                 status,
+			"last_error":             crawlErr.Error(),
+			"last_error_code":        code,
+			"consecutive_failures":   failures,
+			"next_retry_at":          nextRetry,
+			"crawl_interval_minutes": int(interval / time.Minute),
+			"next_crawl_at":          nextCrawl,
+			"updated_at":             now,
+		},
+		"$inc": bson.M{"failed_crawls": 1},
+	})
+	if err != nil {
+		log.Printf("Failed to record crawl failure for %s: %v", source.URL, err)
+	}
 }
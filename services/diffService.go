@@ -0,0 +1,78 @@
+package services
+
+import "strings"
+
+// maxDiffCells bounds the word diff's table; longer texts are shown as replaced outright
+const maxDiffCells = 1000000
+
+// Diff operations
+const (
+	diffEqual  = "equal"
+	diffInsert = "insert"
+	diffDelete = "delete"
+)
+
+// DiffChunk is a run of words kept, added or removed between two versions
+type DiffChunk struct {
+	Op   string `json:"op"` // equal, insert or delete
+	Text string `json:"text"`
+}
+
+// wordDiff compares two texts word by word (longest common subsequence)
+func wordDiff(oldText string, newText string) []DiffChunk {
+	oldWords := strings.Fields(oldText)
+	newWords := strings.Fields(newText)
+
+	chunks := []DiffChunk{}
+	if len(oldWords)*len(newWords) > maxDiffCells {
+		chunks = appendDiffChunk(chunks, diffDelete, oldWords...)
+		return appendDiffChunk(chunks, diffInsert, newWords...)
+	}
+
+	// lcs[i][j] is the common subsequence length of oldWords[i:] and newWords[j:]
+	lcs := make([][]int, len(oldWords)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newWords)+1)
+	}
+	for i := len(oldWords) - 1; i >= 0; i-- {
+		for j := len(newWords) - 1; j >= 0; j-- {
+			if oldWords[i] == newWords[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(oldWords) && j < len(newWords) {
+		switch {
+		case oldWords[i] == newWords[j]:
+			chunks = appendDiffChunk(chunks, diffEqual, oldWords[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			chunks = appendDiffChunk(chunks, diffDelete, oldWords[i])
+			i++
+		default:
+			chunks = appendDiffChunk(chunks, diffInsert, newWords[j])
+			j++
+		}
+	}
+	chunks = appendDiffChunk(chunks, diffDelete, oldWords[i:]...)
+	return appendDiffChunk(chunks, diffInsert, newWords[j:]...)
+}
+
+// appendDiffChunk adds words to the last chunk when it has the same operation
+func appendDiffChunk(chunks []DiffChunk, op string, words ...string) []DiffChunk {
+	if len(words) == 0 {
+		return chunks
+	}
+
+	text := strings.Join(words, " ")
+	if last := len(chunks) - 1; last >= 0 && chunks[last].Op == op {
+		chunks[last].Text += " " + text
+		return chunks
+	}
+	return append(chunks, DiffChunk{Op: op, Text: text})
+}
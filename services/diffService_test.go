@@ -0,0 +1,73 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWordDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []DiffChunk
+	}{
+		{
+			name: "identical",
+			old:  "Prices rise again",
+			new:  "Prices  rise\nagain",
+			want: []DiffChunk{{diffEqual, "Prices rise again"}},
+		},
+		{
+			name: "word replaced",
+			old:  "Prices rise again in May",
+			new:  "Prices fall again in May",
+			want: []DiffChunk{{diffEqual, "Prices"}, {diffDelete, "rise"}, {diffInsert, "fall"}, {diffEqual, "again in May"}},
+		},
+		{
+			name: "words appended",
+			old:  "Minister resigns",
+			new:  "Minister resigns after vote",
+			want: []DiffChunk{{diffEqual, "Minister resigns"}, {diffInsert, "after vote"}},
+		},
+		{
+			name: "words removed",
+			old:  "Breaking: storm hits coast",
+			new:  "Storm hits coast",
+			want: []DiffChunk{{diffDelete, "Breaking: storm"}, {diffInsert, "Storm"}, {diffEqual, "hits coast"}},
+		},
+		{
+			name: "from empty",
+			old:  "",
+			new:  "New summary",
+			want: []DiffChunk{{diffInsert, "New summary"}},
+		},
+		{
+			name: "to empty",
+			old:  "Old summary",
+			new:  "",
+			want: []DiffChunk{{diffDelete, "Old summary"}},
+		},
+		{
+			name: "both empty",
+			want: []DiffChunk{},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := wordDiff(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWordDiffTooLarge(t *testing.T) {
+	oldText := strings.Repeat("a ", 1001)
+	newText := strings.Repeat("b ", 1001)
+
+	got := wordDiff(oldText, newText)
+	if len(got) != 2 || got[0].Op != diffDelete || got[1].Op != diffInsert {
+		t.Errorf("expected a full replacement past maxDiffCells, got %d chunks", len(got))
+	}
+}
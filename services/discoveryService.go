@@ -0,0 +1,222 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-lang-jwt/database"
+	"go-lang-jwt/helpers"
+	"go-lang-jwt/models"
+
+	"github.com/PuerkitoBio/goquery"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DiscoveryResult holds the feed, sitemap and name found for a page
+type DiscoveryResult struct {
+	Name       string `json:"name"`
+	RSSUrl     string `json:"rss_url"`
+	SitemapUrl string `json:"sitemap_url"`
+}
+
+// feedLinkTypes are the <link rel="alternate"> types we treat as feeds
+var feedLinkTypes = []string{
+	"application/rss+xml",
+	"application/atom+xml",
+	"application/rdf+xml",
+	"application/feed+json",
+}
+
+// commonFeedPaths are probed when the page does not advertise a feed
+var commonFeedPaths = []string{
+	"/feed",
+	"/rss",
+	"/rss.xml",
+	"/feed.xml",
+	"/atom.xml",
+	"/index.xml",
+	"/feed.json",
+}
+
+// feedProbeBudget caps the time spent probing commonFeedPaths; each probe waits its turn on the host
+var feedProbeBudget = helpers.GetEnvDuration("DISCOVERY_PROBE_BUDGET", 10*time.Second)
+
+// DiscoverSource inspects a page for feeds, sitemaps and a site name
+func DiscoverSource(ctx context.Context, pageURL string) (*DiscoveryResult, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %v", err)
+	}
+
+	result := &DiscoveryResult{}
+
+	// Step 1: Look at the page itself
+	page, err := fetchURL(ctx, pageURL, &fetchOptions{Accept: acceptPage})
+	if err != nil {
+		return nil, err
+	}
+
+	if looksLikeFeed(page) {
+		// The user pasted a feed URL directly
+		result.RSSUrl = page.URL
+	} else if doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.Body)); err == nil {
+		if finalURL, err := url.Parse(page.URL); err == nil {
+			base = finalURL
+		}
+		result.Name = discoverSiteName(doc)
+		result.RSSUrl = discoverFeedLink(doc, documentBase(doc, base.String()))
+	}
+
+	// Step 2: Probe common feed locations
+	if result.RSSUrl == "" {
+		result.RSSUrl = probeFeedPaths(ctx, base)
+	}
+
+	// Step 3: Read sitemap declarations from robots.txt
+	result.SitemapUrl = discoverSitemap(ctx, base)
+
+	return result, nil
+}
+
+// RefreshSourceDiscovery runs discovery for a stored source and saves what it finds
+func RefreshSourceDiscovery(ctx context.Context, sourceID primitive.ObjectID) (*models.Source, error) {
+	sourceCollection := database.OpenCollection(database.Client, "sources")
+
+	var source models.Source
+	err := sourceCollection.FindOne(ctx, bson.M{"_id": sourceID}).Decode(&source)
+	if err != nil {
+		return nil, fmt.Errorf("source not found: %v", err)
+	}
+
+	discovery, err := DiscoverSource(ctx, source.URL)
+	if err != nil {
+		return nil, fmt.Errorf("discovery failed: %v", err)
+	}
+
+	applyDiscovery(&source, discovery)
+	source.UpdatedAt = time.Now()
+
+	_, err = sourceCollection.UpdateOne(ctx, bson.M{"_id": sourceID}, bson.M{
+		"$set": bson.M{
+			"name":        source.Name,
+			"rss_url":     source.RSSUrl,
+			"sitemap_url": source.SitemapUrl,
+			"updated_at":  source.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update source: %v", err)
+	}
+
+	return &source, nil
+}
+
+// applyDiscovery copies non-empty discovery results onto a source
+func applyDiscovery(source *models.Source, discovery *DiscoveryResult) {
+	if discovery.Name != "" {
+		source.Name = discovery.Name
+	}
+	if discovery.RSSUrl != "" {
+		source.RSSUrl = discovery.RSSUrl
+	}
+	if discovery.SitemapUrl != "" {
+		source.SitemapUrl = discovery.SitemapUrl
+	}
+}
+
+// discoverSiteName prefers og:site_name and falls back to <title>
+func discoverSiteName(doc *goquery.Document) string {
+	name, _ := doc.Find(`meta[property="og:site_name"]`).First().Attr("content")
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		name = strings.TrimSpace(doc.Find("title").First().Text())
+		name = strings.Join(strings.Fields(name), " ")
+	}
+
+	if len(name) > 200 {
+		name = name[:200]
+	}
+	return name
+}
+
+// discoverFeedLink returns the first advertised feed, resolved against the page URL
+func discoverFeedLink(doc *goquery.Document, base *url.URL) string {
+	var feedURL string
+
+	doc.Find(`link[rel~="alternate"][href]`).EachWithBreak(func(i int, s *goquery.Selection) bool {
+		linkType, _ := s.Attr("type")
+		linkType = strings.ToLower(strings.TrimSpace(linkType))
+
+		for _, feedType := range feedLinkTypes {
+			if linkType == feedType {
+				href, _ := s.Attr("href")
+				if feedURL = resolveLink(base, href); feedURL != "" {
+					return false
+				}
+			}
+		}
+		return true
+	})
+
+	return feedURL
+}
+
+// probeFeedPaths tries well-known feed paths on the page's host
+// Probing stops once its time budget is spent, leaving the caller time to finish
+func probeFeedPaths(ctx context.Context, base *url.URL) string {
+	budget := feedProbeBudget
+	if deadline, ok := ctx.Deadline(); ok {
+		if half := time.Until(deadline) / 2; half < budget {
+			budget = half
+		}
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	for _, path := range commonFeedPaths {
+		if probeCtx.Err() != nil {
+			log.Printf("Stopped probing feed paths on %s: time budget spent", base.Host)
+			break
+		}
+
+		candidate := (&url.URL{Scheme: base.Scheme, Host: base.Host, Path: path}).String()
+
+		result, err := fetchURL(probeCtx, candidate, &fetchOptions{Accept: acceptFeed})
+		if err != nil {
+			continue
+		}
+
+		if looksLikeFeed(result) {
+			return result.URL
+		}
+	}
+	return ""
+}
+
+// discoverSitemap returns a Sitemap: line from the host's robots.txt
+// News sitemaps are preferred over general ones
+func discoverSitemap(ctx context.Context, base *url.URL) string {
+	policy, err := robotsFor(ctx, base)
+	if err != nil {
+		log.Printf("No robots.txt for %s: %v", base.Host, err)
+		return ""
+	}
+
+	for _, sitemap := range policy.sitemaps {
+		if strings.Contains(strings.ToLower(sitemap), "news") {
+			return sitemap
+		}
+	}
+	if len(policy.sitemaps) > 0 {
+		return policy.sitemaps[0]
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProbeFeedPathsFindsFeed(t *testing.T) {
+	server := withTestHost(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rss.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel></channel></rss>`))
+	}))
+	base, _ := url.Parse(server.URL)
+
+	if got := probeFeedPaths(context.Background(), base); got != server.URL+"/rss.xml" {
+		t.Errorf("probeFeedPaths = %q, want %s/rss.xml", got, server.URL)
+	}
+}
+
+func TestProbeFeedPathsStopsWhenBudgetSpent(t *testing.T) {
+	var probes atomic.Int32
+	server := withTestHost(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		probes.Add(1)
+		time.Sleep(40 * time.Millisecond)
+		http.NotFound(w, r)
+	}))
+	base, _ := url.Parse(server.URL)
+
+	// The caller's deadline halves the configured budget
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if got := probeFeedPaths(ctx, base); got != "" {
+		t.Errorf("probeFeedPaths = %q, want none", got)
+	}
+	if elapsed := time.Since(start); elapsed > 180*time.Millisecond {
+		t.Errorf("probing took %s, want it to stop after about 100ms", elapsed)
+	}
+	if n := probes.Load(); n >= int32(len(commonFeedPaths)) {
+		t.Errorf("probed %d paths, want fewer than all %d", n, len(commonFeedPaths))
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+
+	"go-lang-jwt/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Generic extractors for any site, tried after site-specific ones
+
+// selectionExtractor treats every element matching a selector as one article
+type selectionExtractor struct {
+	name     string
+	priority int
+	selector string
+}
+
+// headingLinkExtractor takes any link inside a heading; the last resort
+type headingLinkExtractor struct{}
+
+func init() {
+	RegisterExtractor(selectionExtractor{name: "article_tags", priority: 30, selector: "article"})
+	RegisterExtractor(selectionExtractor{
+		name:     "class_names",
+		priority: 20,
+		selector: "div.post, div.entry, div.article-item, div.story, li.story",
+	})
+	RegisterExtractor(headingLinkExtractor{})
+}
+
+func (e selectionExtractor) Name() string { return e.name }
+
+func (e selectionExtractor) Priority() int { return e.priority }
+
+func (e selectionExtractor) Match(source models.Source, page *FetchResult) bool { return true }
+
+func (e selectionExtractor) Extract(doc *goquery.Document, source models.Source) []ArticleData {
+	var articles []ArticleData
+
+	doc.Find(e.selector).Each(func(i int, s *goquery.Selection) {
+		if article := extractFromSelection(s, doc.Url); article != nil {
+			articles = append(articles, *article)
+		}
+	})
+
+	return articles
+}
+
+func (headingLinkExtractor) Name() string { return "heading_links" }
+
+func (headingLinkExtractor) Priority() int { return 10 }
+
+func (headingLinkExtractor) Match(source models.Source, page *FetchResult) bool { return true }
+
+func (headingLinkExtractor) Extract(doc *goquery.Document, source models.Source) []ArticleData {
+	var articles []ArticleData
+
+	doc.Find("h1 a, h2 a, h3 a").Each(func(i int, s *goquery.Selection) {
+		title := strings.TrimSpace(s.Text())
+		href, _ := s.Attr("href")
+
+		url := resolveLink(doc.Url, href)
+		if url == "" || title == "" || len(title) < 10 {
+			return
+		}
+
+		articles = append(articles, ArticleData{
+			Title:   title,
+			URL:     url,
+			Summary: "",
+		})
+	})
+
+	return articles
+}
+
+// extractFromSelection builds an article from a container's first heading, link and paragraph
+// Links are resolved against the document's base URL
+func extractFromSelection(s *goquery.Selection, base *url.URL) *ArticleData {
+	title := s.Find("h1, h2, h3, a").First().Text()
+	title = strings.TrimSpace(title)
+
+	href, _ := s.Find("a").First().Attr("href")
+	url := resolveLink(base, href)
+	if url == "" || title == "" || len(title) < 10 {
+		return nil
+	}
+
+	summary := s.Find("p").First().Text()
+	summary = truncateSummary(strings.TrimSpace(summary))
+
+	article := &ArticleData{
+		Title:   title,
+		URL:     url,
+		Summary: summary,
+	}
+
+	// Dates, authors etc. the item marks up (h-entry, <time>, rel=author)
+	itemMetadata(s, base).applyTo(article)
+	return article
+}
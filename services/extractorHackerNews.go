@@ -0,0 +1,48 @@
+package services
+
+import (
+	"strings"
+
+	"go-lang-jwt/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// hackerNewsExtractor reads the Hacker News front page table
+type hackerNewsExtractor struct{}
+
+func init() {
+	RegisterExtractor(hackerNewsExtractor{})
+}
+
+func (hackerNewsExtractor) Name() string { return "hackernews" }
+
+func (hackerNewsExtractor) Priority() int { return 100 }
+
+func (hackerNewsExtractor) Match(source models.Source, page *FetchResult) bool {
+	return hostMatches(source, page, "news.ycombinator.com")
+}
+
+func (hackerNewsExtractor) Extract(doc *goquery.Document, source models.Source) []ArticleData {
+	var articles []ArticleData
+
+	doc.Find("tr.athing").Each(func(i int, s *goquery.Selection) {
+		titleLink := s.Find("span.titleline > a").First()
+		title := strings.TrimSpace(titleLink.Text())
+		href, _ := titleLink.Attr("href")
+
+		// Make URL absolute ("item?id=" links point back to HN)
+		url := resolveLink(doc.Url, href)
+		if url == "" || title == "" {
+			return
+		}
+
+		articles = append(articles, ArticleData{
+			Title:   title,
+			URL:     url,
+			Summary: "",
+		})
+	})
+
+	return articles
+}
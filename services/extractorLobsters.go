@@ -0,0 +1,47 @@
+package services
+
+import (
+	"strings"
+
+	"go-lang-jwt/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// lobstersExtractor reads the Lobsters story list
+type lobstersExtractor struct{}
+
+func init() {
+	RegisterExtractor(lobstersExtractor{})
+}
+
+func (lobstersExtractor) Name() string { return "lobsters" }
+
+func (lobstersExtractor) Priority() int { return 100 }
+
+func (lobstersExtractor) Match(source models.Source, page *FetchResult) bool {
+	return hostMatches(source, page, "lobste.rs")
+}
+
+func (lobstersExtractor) Extract(doc *goquery.Document, source models.Source) []ArticleData {
+	var articles []ArticleData
+
+	doc.Find("li.story").Each(func(i int, s *goquery.Selection) {
+		titleLink := s.Find("a.u-url").First()
+		title := strings.TrimSpace(titleLink.Text())
+		href, _ := titleLink.Attr("href")
+
+		url := resolveLink(doc.Url, href)
+		if url == "" || title == "" {
+			return
+		}
+
+		articles = append(articles, ArticleData{
+			Title:   title,
+			URL:     url,
+			Summary: "",
+		})
+	})
+
+	return articles
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-lang-jwt/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Extractor turns a fetched HTML page into articles
+// Site-specific extractors live in their own file and register themselves from init()
+type Extractor interface {
+	// Name identifies the extractor in crawl runs
+	Name() string
+	// Priority orders extractors; higher runs first
+	Priority() int
+	// Match reports whether the extractor applies to the source and response
+	Match(source models.Source, page *FetchResult) bool
+	// Extract returns the articles found in the document
+	Extract(doc *goquery.Document, source models.Source) []ArticleData
+}
+
+var (
+	extractors   []Extractor
+	extractorsMu sync.RWMutex
+)
+
+// RegisterExtractor adds an extractor to the registry
+func RegisterExtractor(extractor Extractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+
+	extractors = append(extractors, extractor)
+	sort.SliceStable(extractors, func(i, j int) bool {
+		return extractors[i].Priority() > extractors[j].Priority()
+	})
+}
+
+// registeredExtractors returns a snapshot of the registry in priority order
+func registeredExtractors() []Extractor {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+
+	return append([]Extractor(nil), extractors...)
+}
+
+// runExtractors tries matching extractors in priority order until one finds articles
+// A non-empty only runs just that extractor, whether or not it matches
+func runExtractors(doc *goquery.Document, source models.Source, page *FetchResult, only string) ([]ArticleData, string) {
+	for _, extractor := range registeredExtractors() {
+		if only != "" && extractor.Name() != only {
+			continue
+		}
+		if only == "" && !extractor.Match(source, page) {
+			continue
+		}
+
+		if articles := extractor.Extract(doc, source); len(articles) > 0 {
+			return articles, extractor.Name()
+		}
+	}
+	return nil, ""
+}
+
+// extractorExists reports whether an extractor is registered under name
+func extractorExists(name string) bool {
+	for _, extractor := range registeredExtractors() {
+		if extractor.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hostMatches reports whether the source or the fetched page is on the given host or a subdomain
+func hostMatches(source models.Source, page *FetchResult, host string) bool {
+	candidates := []string{source.URL}
+	if page != nil {
+		candidates = append(candidates, page.URL)
+	}
+
+	for _, candidate := range candidates {
+		parsed, err := url.Parse(candidate)
+		if err != nil {
+			continue
+		}
+
+		hostname := strings.ToLower(parsed.Hostname())
+		if hostname == host || strings.HasSuffix(hostname, "."+host) {
+			return true
+		}
+	}
+	return false
+}
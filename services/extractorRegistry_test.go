@@ -0,0 +1,112 @@
+package services
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-lang-jwt/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// stubExtractor matches when told to and returns one article named after itself
+type stubExtractor struct {
+	name     string
+	priority int
+	matches  bool
+	empty    bool
+}
+
+func (e stubExtractor) Name() string { return e.name }
+
+func (e stubExtractor) Priority() int { return e.priority }
+
+func (e stubExtractor) Match(source models.Source, page *FetchResult) bool { return e.matches }
+
+func (e stubExtractor) Extract(doc *goquery.Document, source models.Source) []ArticleData {
+	if e.empty {
+		return nil
+	}
+	return []ArticleData{{Title: e.name}}
+}
+
+// withExtractors swaps the registry for the given extractors, registered in order
+func withExtractors(t *testing.T, registered ...Extractor) {
+	extractorsMu.Lock()
+	saved := extractors
+	extractors = nil
+	extractorsMu.Unlock()
+	t.Cleanup(func() {
+		extractorsMu.Lock()
+		extractors = saved
+		extractorsMu.Unlock()
+	})
+
+	for _, extractor := range registered {
+		RegisterExtractor(extractor)
+	}
+}
+
+func extractorNames(list []Extractor) []string {
+	var names []string
+	for _, extractor := range list {
+		names = append(names, extractor.Name())
+	}
+	return names
+}
+
+func TestBuiltinExtractorOrder(t *testing.T) {
+	want := []string{rulesExtractorName, "hackernews", "lobsters", "article_tags", "class_names", "heading_links"}
+	if got := extractorNames(registeredExtractors()); !reflect.DeepEqual(got, want) {
+		t.Errorf("registered extractors = %v, want %v", got, want)
+	}
+}
+
+func TestRegisterExtractorKeepsPriorityOrder(t *testing.T) {
+	withExtractors(t,
+		stubExtractor{name: "low", priority: 1},
+		stubExtractor{name: "high", priority: 50},
+		stubExtractor{name: "first-tie", priority: 10},
+		stubExtractor{name: "second-tie", priority: 10},
+	)
+
+	want := []string{"high", "first-tie", "second-tie", "low"}
+	if got := extractorNames(registeredExtractors()); !reflect.DeepEqual(got, want) {
+		t.Errorf("registered extractors = %v, want %v", got, want)
+	}
+}
+
+func TestRunExtractors(t *testing.T) {
+	withExtractors(t,
+		stubExtractor{name: "site", priority: 100, matches: false},
+		stubExtractor{name: "empty", priority: 50, matches: true, empty: true},
+		stubExtractor{name: "generic", priority: 20, matches: true},
+		stubExtractor{name: "fallback", priority: 10, matches: true},
+	)
+
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader("<html></html>"))
+	doc.Url, _ = url.Parse("https://news.example/")
+
+	tests := []struct {
+		only string
+		want string
+	}{
+		{"", "generic"},  // "site" doesn't match and "empty" finds nothing
+		{"site", "site"}, // a forced extractor runs even without a match
+		{"fallback", "fallback"},
+		{"empty", ""},
+		{"missing", ""},
+	}
+
+	for _, tt := range tests {
+		articles, name := runExtractors(doc, models.Source{URL: "https://news.example/"}, nil, tt.only)
+		if name != tt.want {
+			t.Errorf("runExtractors(only=%q) used %q, want %q", tt.only, name, tt.want)
+		}
+		if (name == "") != (len(articles) == 0) {
+			t.Errorf("runExtractors(only=%q) returned %d articles from %q", tt.only, len(articles), name)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-lang-jwt/helpers"
+	"go-lang-jwt/models"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// rulesExtractor applies a source's admin-defined extraction rules before any built-in extractor
+type rulesExtractor struct{}
+
+func init() {
+	RegisterExtractor(rulesExtractor{})
+}
+
+// compiledRules holds the parsed URL filters of a rule set
+type compiledRules struct {
+	rules   *models.ExtractionRules
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+// rulesExtractorName identifies the rules extractor in crawl runs
+const rulesExtractorName = "rules"
+
+func (rulesExtractor) Name() string { return rulesExtractorName }
+
+func (rulesExtractor) Priority() int { return 1000 }
+
+func (rulesExtractor) Match(source models.Source, page *FetchResult) bool {
+	return source.ExtractionRules != nil
+}
+
+func (rulesExtractor) Extract(doc *goquery.Document, source models.Source) []ArticleData {
+	if source.ExtractionRules == nil {
+		return nil
+	}
+
+	compiled, err := compileExtractionRules(source.ExtractionRules)
+	if err != nil {
+		log.Printf("Invalid extraction rules for %s: %v", source.URL, err)
+		return nil
+	}
+
+	var articles []ArticleData
+	doc.Find(compiled.rules.ItemSelector).Each(func(i int, item *goquery.Selection) {
+		if article := compiled.extractItem(item, doc.Url); article != nil {
+			articles = append(articles, *article)
+		}
+	})
+	return articles
+}
+
+// compileExtractionRules checks every selector and pattern of a rule set
+func compileExtractionRules(rules *models.ExtractionRules) (*compiledRules, error) {
+	if strings.TrimSpace(rules.ItemSelector) == "" {
+		return nil, fmt.Errorf("item_selector is required")
+	}
+
+	selectors := map[string]string{
+		"item_selector":    rules.ItemSelector,
+		"title_selector":   rules.TitleSelector,
+		"link_selector":    rules.LinkSelector,
+		"summary_selector": rules.SummarySelector,
+		"author_selector":  rules.AuthorSelector,
+		"date_selector":    rules.DateSelector,
+	}
+	for field, selector := range selectors {
+		if selector == "" {
+			continue
+		}
+		if _, err := cascadia.ParseGroup(selector); err != nil {
+			return nil, fmt.Errorf("%s: %v", field, err)
+		}
+	}
+
+	compiled := &compiledRules{rules: rules}
+
+	var err error
+	if rules.IncludeURLPattern != "" {
+		if compiled.include, err = regexp.Compile(rules.IncludeURLPattern); err != nil {
+			return nil, fmt.Errorf("include_url_pattern: %v", err)
+		}
+	}
+	if rules.ExcludeURLPattern != "" {
+		if compiled.exclude, err = regexp.Compile(rules.ExcludeURLPattern); err != nil {
+			return nil, fmt.Errorf("exclude_url_pattern: %v", err)
+		}
+	}
+
+	return compiled, nil
+}
+
+// extractItem builds an article from one item, or nil when the item has no title or link
+// or its URL is filtered out
+func (compiled *compiledRules) extractItem(item *goquery.Selection, base *url.URL) *ArticleData {
+	rules := compiled.rules
+
+	// Link: the selected element, else the first link, else the item itself
+	link := item
+	if rules.LinkSelector != "" {
+		link = item.Find(rules.LinkSelector).First()
+	} else if !item.Is("a") {
+		link = item.Find("a").First()
+	}
+
+	attribute := rules.LinkAttribute
+	if attribute == "" {
+		attribute = "href"
+	}
+
+	href, _ := link.Attr(attribute)
+	articleURL := resolveLink(base, href)
+	if articleURL == "" {
+		return nil
+	}
+	if compiled.include != nil && !compiled.include.MatchString(articleURL) {
+		return nil
+	}
+	if compiled.exclude != nil && compiled.exclude.MatchString(articleURL) {
+		return nil
+	}
+
+	title := strings.TrimSpace(link.Text())
+	if rules.TitleSelector != "" {
+		title = selectionText(item, rules.TitleSelector)
+	}
+	if title == "" {
+		return nil
+	}
+
+	article := &ArticleData{
+		Title: title,
+		URL:   articleURL,
+	}
+
+	if rules.SummarySelector != "" {
+		article.Summary = truncateSummary(selectionText(item, rules.SummarySelector))
+	}
+	if rules.AuthorSelector != "" {
+		article.Author = selectionText(item, rules.AuthorSelector)
+	}
+	if rules.DateSelector != "" {
+		article.PublishedAt = compiled.itemDate(item)
+	}
+
+	itemMetadata(item, base).applyTo(article)
+	return article
+}
+
+// itemDate reads and parses the item's publication date
+func (compiled *compiledRules) itemDate(item *goquery.Selection) *time.Time {
+	rules := compiled.rules
+	element := item.Find(rules.DateSelector).First()
+
+	value := strings.TrimSpace(element.Text())
+	if rules.DateAttribute != "" {
+		value, _ = element.Attr(rules.DateAttribute)
+	} else if datetime, ok := element.Attr("datetime"); ok {
+		value = datetime
+	}
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		return nil
+	}
+
+	if rules.DateFormat != "" {
+		parsed, err := time.Parse(rules.DateFormat, value)
+		if err != nil {
+			return nil
+		}
+		parsed = parsed.UTC()
+		return &parsed
+	}
+	return helpers.ParseDate(value)
+}
+
+// selectionText returns the trimmed text of the first element matching selector
+func selectionText(item *goquery.Selection, selector string) string {
+	return strings.TrimSpace(item.Find(selector).First().Text())
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go-lang-jwt/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestCompileExtractionRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules models.ExtractionRules
+		err   string // expected error prefix, "" for valid
+	}{
+		{"item selector only", models.ExtractionRules{ItemSelector: "article.post"}, ""},
+		{"every field", models.ExtractionRules{
+			ItemSelector:      "ul.news > li",
+			TitleSelector:     "h2, h3",
+			LinkSelector:      "a[data-id]",
+			SummarySelector:   "p:first-of-type",
+			AuthorSelector:    ".byline",
+			DateSelector:      "time",
+			IncludeURLPattern: `^https://news\.example/\d{4}/`,
+			ExcludeURLPattern: `/sponsored/`,
+		}, ""},
+		{"missing item selector", models.ExtractionRules{TitleSelector: "h2"}, "item_selector is required"},
+		{"blank item selector", models.ExtractionRules{ItemSelector: "  "}, "item_selector is required"},
+		{"bad item selector", models.ExtractionRules{ItemSelector: "div["}, "item_selector: "},
+		{"bad title selector", models.ExtractionRules{ItemSelector: "li", TitleSelector: "h2:nope"}, "title_selector: "},
+		{"bad date selector", models.ExtractionRules{ItemSelector: "li", DateSelector: ">>"}, "date_selector: "},
+		{"bad include pattern", models.ExtractionRules{ItemSelector: "li", IncludeURLPattern: "(unclosed"}, "include_url_pattern: "},
+		{"bad exclude pattern", models.ExtractionRules{ItemSelector: "li", ExcludeURLPattern: "[z-a]"}, "exclude_url_pattern: "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiled, err := compileExtractionRules(&tt.rules)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if (compiled.include != nil) != (tt.rules.IncludeURLPattern != "") || (compiled.exclude != nil) != (tt.rules.ExcludeURLPattern != "") {
+					t.Errorf("URL filters not compiled: include %v, exclude %v", compiled.include, compiled.exclude)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+				t.Errorf("error = %v, want one starting with %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestRulesExtractorExtract(t *testing.T) {
+	page := `<html><body><ul class="news">
+		<li>
+			<h3>Council approves the new budget</h3>
+			<span class="go" data-href="/2026/10/budget">Read</span>
+			<p>The vote was close.</p>
+			<span class="byline"> Jane Doe </span>
+			<span class="when" data-ts="16.10.2026 09:30">yesterday</span>
+		</li>
+		<li>
+			<h3>Sponsored: buy now</h3>
+			<span class="go" data-href="/sponsored/deal">Read</span>
+		</li>
+		<li>
+			<h3>Old archive piece</h3>
+			<span class="go" data-href="/archive/old">Read</span>
+		</li>
+		<li>
+			<h3>No link at all</h3>
+		</li>
+	</ul></body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Url, _ = url.Parse("https://news.example/latest")
+
+	source := models.Source{
+		URL: "https://news.example/latest",
+		ExtractionRules: &models.ExtractionRules{
+			ItemSelector:      "ul.news > li",
+			TitleSelector:     "h3",
+			LinkSelector:      "span.go",
+			LinkAttribute:     "data-href",
+			SummarySelector:   "p",
+			AuthorSelector:    ".byline",
+			DateSelector:      ".when",
+			DateAttribute:     "data-ts",
+			DateFormat:        "02.01.2006 15:04",
+			IncludeURLPattern: `^https://news\.example/(2026|sponsored)/`,
+			ExcludeURLPattern: `/sponsored/`,
+		},
+	}
+
+	articles := rulesExtractor{}.Extract(doc, source)
+	if len(articles) != 1 {
+		t.Fatalf("got %d articles, want only the budget story: %+v", len(articles), articles)
+	}
+
+	article := articles[0]
+	if article.Title != "Council approves the new budget" || article.URL != "https://news.example/2026/10/budget" {
+		t.Errorf("article = %q at %s", article.Title, article.URL)
+	}
+	if article.Summary != "The vote was close." || article.Author != "Jane Doe" {
+		t.Errorf("summary %q, author %q", article.Summary, article.Author)
+	}
+	want := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	if article.PublishedAt == nil || !article.PublishedAt.Equal(want) {
+		t.Errorf("published = %v, want %s", article.PublishedAt, want)
+	}
+
+	// Broken rules extract nothing rather than falling back to guesses
+	source.ExtractionRules = &models.ExtractionRules{ItemSelector: "li", ExcludeURLPattern: "("}
+	if articles := (rulesExtractor{}).Extract(doc, source); len(articles) != 0 {
+		t.Errorf("invalid rules extracted %d articles", len(articles))
+	}
+}
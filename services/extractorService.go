@@ -1,14 +1,12 @@
 package services
 
 import (
+	"bytes"
 	"context"
-	"errors"
 	"fmt"
-	"net/http"
-	"strings"
+	"log"
 	"time"
 
-	"go-lang-jwt/helpers"
 	"go-lang-jwt/models"
 
 	"github.com/PuerkitoBio/goquery"
@@ -16,186 +14,161 @@ import (
 
 // ArticleData represents extracted article information
 type ArticleData struct {
-	Title       string
-	URL         string
-	Summary     string
-	PublishedAt *time.Time
-	Author      string
-	ContentHash string
+	Title        string          `json:"title"`
+	URL          string          `json:"url"`
+	CanonicalURL string          `json:"canonical_url"`
+	GUID         string          `json:"guid"`
+	Summary      string          `json:"summary"`
+	PublishedAt  *time.Time      `json:"published_at"`
+	Author       string          `json:"author"`
+	Image        string          `json:"image"`
+	Section      string          `json:"section"`
+	Content      *articleContent `json:"-"`                      // main text, when the source extracts content
+	ContentHash  string          `json:"content_hash,omitempty"` // set by the crawler once the article is complete
+
+	titleFromURL bool // the title was derived from the URL's last path segment
 }
 
-// ExtractArticles fetches URL and extracts articles
-func ExtractArticles(ctx context.Context, source models.Source) ([]ArticleData, error) {
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+// Extraction strategies, recorded on each crawl run
+const (
+	strategyFeed     = "feed"
+	strategySitemap  = "sitemap"
+	strategyPageFeed = "page_feed"
+	strategyHTML     = "html"
+)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", source.URL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
+// ExtractResult is the outcome of extracting articles from a source
+type ExtractResult struct {
+	Articles    []ArticleData
+	NotModified bool   // the chosen strategy answered 304 Not Modified
+	Strategy    string // which strategy produced the articles
+	Extractor   string // which HTML extractor matched, for the html strategy
+	Validators  []models.FetchValidator
+	Warnings    []string // fallbacks and other problems worth showing in a preview
+
+	// Fetch statistics for the whole crawl
+	HTTPStatus      int // status of the last response
+	BytesDownloaded int64
+}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; FeedAggregator/1.0)")
+// ExtractArticles fetches URL and extracts articles
+// The result is returned even on error so the fetch statistics can be recorded
+func ExtractArticles(ctx context.Context, source models.Source) (*ExtractResult, error) {
+	return runExtraction(ctx, source, newValidatorSet(source), extractOptions{})
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch URL: %v", err)
-	}
-	defer resp.Body.Close()
+// extractOptions forces parts of the pipeline (used by previews)
+type extractOptions struct {
+	strategy  string // feed, sitemap or html; empty tries them in order
+	extractor string // run only this HTML extractor, ignoring its Match
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("bad status code: %d", resp.StatusCode)
+func runExtraction(ctx context.Context, source models.Source, validators *validatorSet, opts extractOptions) (*ExtractResult, error) {
+	result, err := extractArticles(ctx, source, validators, opts)
+	if result == nil {
+		result = &ExtractResult{}
 	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	result.HTTPStatus = validators.lastStatus
+	result.BytesDownloaded = validators.bytesDownloaded
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %v", err)
+		return result, err
 	}
 
-	var articles []ArticleData
-
-	// Strategy 1: Hacker News specific
-	if strings.Contains(source.URL, "news.ycombinator.com") {
-		articles = extractHackerNews(doc, source)
-	}
+	result.Validators = validators.list()
+	return result, nil
+}
 
-	// Strategy 2: Lobsters specific
-	if len(articles) == 0 && strings.Contains(source.URL, "lobste.rs") {
-		articles = extractLobsters(doc, source)
-	}
+// warn records a non-fatal problem with the extraction
+func (result *ExtractResult) warn(format string, args ...interface{}) {
+	result.Warnings = append(result.Warnings, fmt.Sprintf(format, args...))
+}
 
-	// Strategy 3: Generic <article> tags
-	if len(articles) == 0 {
-		doc.Find("article").Each(func(i int, s *goquery.Selection) {
-			if article := extractFromSelection(s, source); article != nil {
-				articles = append(articles, *article)
+// extractArticles runs the feed, sitemap and HTML strategies in order
+// Sources with extraction rules go straight to the page so the rules apply
+func extractArticles(ctx context.Context, source models.Source, validators *validatorSet, opts extractOptions) (*ExtractResult, error) {
+	hasRules := source.ExtractionRules != nil
+	auto := opts.strategy == ""
+	var warnings []string
+
+	// Prefer the native feed when the source has one
+	if source.RSSUrl != "" && !hasRules && (auto || opts.strategy == strategyFeed) {
+		articles, notModified, err := extractFromFeed(ctx, source.RSSUrl, validators)
+		if err == nil && (notModified || len(articles) > 0) {
+			return &ExtractResult{Articles: articles, NotModified: notModified, Strategy: strategyFeed}, nil
+		}
+		if !auto {
+			if err == nil {
+				err = parseError("no articles found in feed")
 			}
-		})
+			return nil, err
+		}
+		log.Printf("Feed extraction failed for %s, falling back to HTML: %v", source.RSSUrl, err)
+		warnings = append(warnings, fmt.Sprintf("feed %s unusable, fell back: %v", source.RSSUrl, err))
 	}
 
-	// Strategy 4: Common class names
-	if len(articles) == 0 {
-		doc.Find("div.post, div.entry, div.article-item, div.story, li.story").Each(func(i int, s *goquery.Selection) {
-			if article := extractFromSelection(s, source); article != nil {
-				articles = append(articles, *article)
+	// Then the sitemap, limited to entries changed since the last crawl
+	if source.SitemapUrl != "" && !hasRules && (auto || opts.strategy == strategySitemap) {
+		articles, seen, err := extractFromSitemap(ctx, source.SitemapUrl, source.LastCrawledAt, validators)
+		if err == nil && seen > 0 {
+			return &ExtractResult{Articles: articles, Strategy: strategySitemap, Warnings: warnings}, nil
+		}
+		if !auto {
+			if err == nil {
+				err = parseError("no entries found in sitemap")
 			}
-		})
+			return nil, err
+		}
+		log.Printf("Sitemap extraction failed for %s, falling back to HTML: %v", source.SitemapUrl, err)
+		warnings = append(warnings, fmt.Sprintf("sitemap %s unusable, fell back: %v", source.SitemapUrl, err))
 	}
 
-	// Strategy 5: Any link with heading
-	if len(articles) == 0 {
-		doc.Find("h1 a, h2 a, h3 a").Each(func(i int, s *goquery.Selection) {
-			title := strings.TrimSpace(s.Text())
-			url, exists := s.Attr("href")
-
-			if !exists || title == "" || len(title) < 10 {
-				return
-			}
-
-			if strings.HasPrefix(url, "/") {
-				url = source.URL + url
-			}
-
-			articles = append(articles, ArticleData{
-				Title:       title,
-				URL:         url,
-				Summary:     "",
-				ContentHash: helpers.GenerateContentHash(title, ""),
-			})
-		})
+	if opts.strategy == strategySitemap {
+		return nil, parseError("no sitemap found for this source")
 	}
 
-	if len(articles) == 0 {
-		return nil, errors.New("no articles found on page")
+	result, err := validators.fetch(ctx, source.URL, acceptPage)
+	if err != nil {
+		return nil, err
 	}
 
-	// Limit to 50 articles per crawl
-	if len(articles) > 50 {
-		articles = articles[:50]
+	if result.NotModified {
+		return &ExtractResult{NotModified: true, Warnings: warnings}, nil
 	}
 
-	return articles, nil
-}
-
-func extractHackerNews(doc *goquery.Document, source models.Source) []ArticleData {
-	var articles []ArticleData
-
-	doc.Find("tr.athing").Each(func(i int, s *goquery.Selection) {
-		titleLink := s.Find("span.titleline > a").First()
-		title := strings.TrimSpace(titleLink.Text())
-		url, exists := titleLink.Attr("href")
-
-		if !exists || title == "" {
-			return
-		}
-
-		// Make URL absolute
-		if strings.HasPrefix(url, "item?id=") {
-			url = "https://news.ycombinator.com/" + url
-		}
-
-		articles = append(articles, ArticleData{
-			Title:       title,
-			URL:         url,
-			Summary:     "",
-			ContentHash: helpers.GenerateContentHash(title, ""),
-		})
-	})
-
-	return articles
-}
-
-func extractLobsters(doc *goquery.Document, source models.Source) []ArticleData {
-	var articles []ArticleData
-
-	doc.Find("li.story").Each(func(i int, s *goquery.Selection) {
-		titleLink := s.Find("a.u-url").First()
-		title := strings.TrimSpace(titleLink.Text())
-		url, exists := titleLink.Attr("href")
-
-		if !exists || title == "" {
-			return
+	// The source URL may itself point at a feed
+	if looksLikeFeed(result) && !hasRules && opts.strategy != strategyHTML {
+		articles, err := parseFeed(result.Body)
+		if err != nil {
+			return nil, parseError(err.Error())
 		}
-
-		if strings.HasPrefix(url, "/") {
-			url = "https://lobste.rs" + url
+		articles = resolveFeedLinks(articles, result.URL)
+		if len(articles) == 0 {
+			return nil, parseError("no articles found in feed")
 		}
+		return &ExtractResult{Articles: articles, Strategy: strategyPageFeed, Warnings: warnings}, nil
+	}
 
-		articles = append(articles, ArticleData{
-			Title:       title,
-			URL:         url,
-			Summary:     "",
-			ContentHash: helpers.GenerateContentHash(title, ""),
-		})
-	})
-
-	return articles
-}
-
-// Helper function
-func extractFromSelection(s *goquery.Selection, source models.Source) *ArticleData {
-	title := s.Find("h1, h2, h3, a").First().Text()
-	title = strings.TrimSpace(title)
-
-	url, exists := s.Find("a").First().Attr("href")
-	if !exists || url == "" || title == "" || len(title) < 10 {
-		return nil
+	if opts.strategy == strategyFeed {
+		return nil, parseError("no feed found for this source")
 	}
 
-	if strings.HasPrefix(url, "/") {
-		url = source.URL + url
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(result.Body))
+	if err != nil {
+		return nil, parseError(fmt.Sprintf("failed to parse HTML: %v", err))
 	}
 
-	summary := s.Find("p").First().Text()
-	summary = strings.TrimSpace(summary)
-	if len(summary) > 500 {
-		summary = summary[:500] + "..."
+	// Extractors resolve links against the final URL and any <base href>
+	doc.Url = documentBase(doc, result.URL)
+
+	// HTML: the registered extractors - source rules, then site-specific ones, then generic
+	articles, extractor := runExtractors(doc, source, result, opts.extractor)
+	if len(articles) == 0 {
+		return nil, parseError("no articles found on page")
 	}
 
-	return &ArticleData{
-		Title:       title,
-		URL:         url,
-		Summary:     summary,
-		ContentHash: helpers.GenerateContentHash(title, summary),
+	extracted := &ExtractResult{Articles: articles, Strategy: strategyHTML, Extractor: extractor, Warnings: warnings}
+	if hasRules && extractor != rulesExtractorName {
+		extracted.warn("extraction rules matched nothing; used the %s extractor", extractor)
 	}
+	return extracted, nil
 }
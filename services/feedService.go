@@ -16,6 +16,14 @@ import (
 type FeedArticle struct {
 	Article models.Article `json:"article"`
 	Source  models.Source  `json:"source"`
+	AlsoOn  []FeedMention  `json:"also_on"` // other sources that published the same content
+}
+
+// FeedMention attributes an article to another source that carried it
+type FeedMention struct {
+	SourceID primitive.ObjectID `json:"source_id"`
+	Name     string             `json:"name"`
+	URL      string             `json:"url"` // the article on that source
 }
 
 // GetUserFeed returns paginated articles from user's subscribed sources
@@ -39,46 +47,100 @@ func GetUserFeed(ctx context.Context, userID string, page int, limit int) ([]Fee
 		sourceMap[sub.Source.ID] = sub.Source
 	}
 
-	// Get articles from subscribed sources
+	// Get articles from subscribed sources, including ones another source published first
 	articleCollection := database.OpenCollection(database.Client, "articles")
+	filter := bson.M{"$or": []bson.M{
+		{"source_id": bson.M{"$in": sourceIDs}},
+		{"source_ids": bson.M{"$in": sourceIDs}},
+	}}
 
 	// Count total articles
-	totalCount, err := articleCollection.CountDocuments(ctx, bson.M{
-		"source_id": bson.M{"$in": sourceIDs},
-	})
+	totalCount, err := articleCollection.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count articles: %v", err)
 	}
 
-	// Calculate pagination
+	// Calculate pagination; full content is fetched per article
 	skip := (page - 1) * limit
 	opts := options.Find().
 		SetSort(bson.D{{Key: "discovered_at", Value: -1}}).
 		SetSkip(int64(skip)).
-		SetLimit(int64(limit))
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"content_html": 0, "content_text": 0})
 
 	// Fetch articles
-	cursor, err := articleCollection.Find(ctx, bson.M{
-		"source_id": bson.M{"$in": sourceIDs},
-	}, opts)
+	cursor, err := articleCollection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to fetch articles: %v", err)
 	}
 	defer cursor.Close(ctx)
 
-	// Decode and combine with source info
-	var feed []FeedArticle
+	var articles []models.Article
 	for cursor.Next(ctx) {
 		var article models.Article
 		if err := cursor.Decode(&article); err != nil {
 			continue
 		}
+		articles = append(articles, article)
+	}
+
+	// Linked articles may come from sources the user doesn't follow
+	if err := loadMissingSources(ctx, articles, sourceMap); err != nil {
+		return nil, 0, err
+	}
+
+	// Combine with source info and "also on" attribution
+	var feed []FeedArticle
+	for _, article := range articles {
+		alsoOn := []FeedMention{}
+		for _, mention := range article.Mentions {
+			alsoOn = append(alsoOn, FeedMention{
+				SourceID: mention.Source_id,
+				Name:     sourceMap[mention.Source_id].Name,
+				URL:      mention.URL,
+			})
+		}
 
 		feed = append(feed, FeedArticle{
 			Article: article,
 			Source:  sourceMap[article.Source_id],
+			AlsoOn:  alsoOn,
 		})
 	}
 
 	return feed, totalCount, nil
 }
+
+// loadMissingSources adds the articles' sources and mentioned sources missing from sourceMap
+func loadMissingSources(ctx context.Context, articles []models.Article, sourceMap map[primitive.ObjectID]models.Source) error {
+	var missing []primitive.ObjectID
+	for _, article := range articles {
+		ids := []primitive.ObjectID{article.Source_id}
+		for _, mention := range article.Mentions {
+			ids = append(ids, mention.Source_id)
+		}
+		for _, id := range ids {
+			if _, ok := sourceMap[id]; !ok {
+				missing = append(missing, id)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	sourceCollection := database.OpenCollection(database.Client, "sources")
+	cursor, err := sourceCollection.Find(ctx, bson.M{"_id": bson.M{"$in": missing}})
+	if err != nil {
+		return fmt.Errorf("failed to fetch sources: %v", err)
+	}
+
+	var sources []models.Source
+	if err := cursor.All(ctx, &sources); err != nil {
+		return fmt.Errorf("failed to fetch sources: %v", err)
+	}
+	for _, source := range sources {
+		sourceMap[source.ID] = source
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-lang-jwt/helpers"
+)
+
+// fetchLimits bounds every crawler fetch; each value can be set through the environment
+type fetchLimits struct {
+	MaxBodyBytes   int64
+	MaxRedirects   int
+	ConnectTimeout time.Duration
+	TLSTimeout     time.Duration
+	HeaderTimeout  time.Duration
+	TotalTimeout   time.Duration
+}
+
+var crawlerLimits = fetchLimits{
+	MaxBodyBytes:   int64(helpers.GetEnvInt("CRAWLER_MAX_BODY_BYTES", 10*1024*1024)),
+	MaxRedirects:   helpers.GetEnvInt("CRAWLER_MAX_REDIRECTS", 5),
+	ConnectTimeout: helpers.GetEnvDuration("CRAWLER_CONNECT_TIMEOUT", 10*time.Second),
+	TLSTimeout:     helpers.GetEnvDuration("CRAWLER_TLS_TIMEOUT", 10*time.Second),
+	HeaderTimeout:  helpers.GetEnvDuration("CRAWLER_HEADER_TIMEOUT", 15*time.Second),
+	TotalTimeout:   helpers.GetEnvDuration("CRAWLER_TOTAL_TIMEOUT", 30*time.Second),
+}
+
+// Content kinds a strategy accepts
+const (
+	contentHTML = "html"
+	contentXML  = "xml"
+	contentJSON = "json"
+)
+
+// allowedContentTypes maps each content kind to its accepted MIME types
+var allowedContentTypes = map[string][]string{
+	contentHTML: helpers.GetEnvList("CRAWLER_HTML_TYPES", "text/html,application/xhtml+xml"),
+	contentXML: helpers.GetEnvList("CRAWLER_XML_TYPES",
+		"application/xml,text/xml,application/rss+xml,application/atom+xml,application/rdf+xml,application/x-gzip,application/gzip"),
+	contentJSON: helpers.GetEnvList("CRAWLER_JSON_TYPES", "application/json,application/feed+json"),
+}
+
+// Content kinds accepted by each fetch
+var (
+	acceptPage    = []string{contentHTML, contentXML, contentJSON} // the page may itself be a feed
+	acceptFeed    = []string{contentXML, contentJSON}
+	acceptSitemap = []string{contentXML}
+)
+
+// acceptHeader builds an Accept header for the given content kinds
+func acceptHeader(kinds []string) string {
+	var types []string
+	for _, kind := range kinds {
+		types = append(types, allowedContentTypes[kind]...)
+	}
+	return strings.Join(types, ", ")
+}
+
+// checkContentType rejects responses whose MIME type none of the kinds allow
+// A missing Content-Type is sniffed from the body
+func checkContentType(header string, body []byte, kinds []string) error {
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header)
+	if header == "" || err != nil {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(body))
+	}
+	mediaType = strings.ToLower(mediaType)
+
+	for _, kind := range kinds {
+		for _, allowed := range allowedContentTypes[kind] {
+			if mediaType == allowed {
+				return nil
+			}
+		}
+	}
+
+	return &CrawlError{
+		Code:    CrawlErrorUnsupportedContentType,
+		Message: fmt.Sprintf("unsupported content type %q (expected %s)", mediaType, strings.Join(kinds, " or ")),
+	}
+}
+
+// readLimitedBody reads at most MaxBodyBytes, failing instead of truncating
+func readLimitedBody(body io.Reader, contentLength int64) ([]byte, error) {
+	limit := crawlerLimits.MaxBodyBytes
+	if contentLength > limit {
+		return nil, responseTooLargeError(limit)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, responseTooLargeError(limit)
+	}
+	return data, nil
+}
+
+func responseTooLargeError(limit int64) error {
+	return &CrawlError{
+		Code:    CrawlErrorResponseTooLarge,
+		Message: fmt.Sprintf("response exceeds %d bytes", limit),
+	}
+}
+
+// classifyFetchError maps transport failures to distinct crawl error codes
+func classifyFetchError(err error) error {
+	if err == nil || crawlErrorCode(err) != "" {
+		return err
+	}
+
+	message := err.Error()
+
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	isDialTimeout := (errors.As(err, &opErr) && opErr.Op == "dial" && opErr.Timeout()) ||
+		(errors.As(err, &dnsErr) && dnsErr.IsTimeout)
+
+	switch {
+	case isDialTimeout:
+		return &CrawlError{Code: CrawlErrorConnectTimeout, Message: "connect timeout: " + message}
+	case strings.Contains(message, "TLS handshake timeout"):
+		return &CrawlError{Code: CrawlErrorTLSTimeout, Message: "TLS handshake timeout: " + message}
+	case strings.Contains(message, "timeout awaiting response headers"):
+		return &CrawlError{Code: CrawlErrorHeaderTimeout, Message: "response header timeout: " + message}
+	case errors.Is(err, context.DeadlineExceeded) || strings.Contains(message, "Client.Timeout"):
+		return &CrawlError{Code: CrawlErrorTimeout, Message: "request timeout: " + message}
+	case dnsErr != nil:
+		return &CrawlError{Code: CrawlErrorDNS, Message: "DNS lookup failed: " + message}
+	case opErr != nil:
+		return &CrawlError{Code: CrawlErrorNetwork, Message: "network error: " + message}
+	}
+
+	return err
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"go-lang-jwt/models"
+)
+
+// crawlerUserAgent identifies the crawler to publishers
+const crawlerUserAgent = "Mozilla/5.0 (compatible; FeedAggregator/1.0)"
+
+// FetchResult holds a downloaded response
+type FetchResult struct {
+	URL        string // final URL after redirects
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	NotModified  bool // server answered 304 to a conditional request
+	ETag         string
+	LastModified string
+}
+
+// fetchOptions tunes a single fetch
+type fetchOptions struct {
+	ETag         string   // sent as If-None-Match
+	LastModified string   // sent as If-Modified-Since
+	Accept       []string // content kinds the caller can parse; empty accepts anything
+}
+
+var crawlerClient = &http.Client{
+	Timeout:       crawlerLimits.TotalTimeout,
+	Transport:     newCrawlerTransport(),
+	CheckRedirect: checkRedirect,
+}
+
+// fetchURL downloads a URL and returns the response body
+// The host's robots.txt is honored; conditional requests answered with 304
+// return NotModified and an empty body
+func fetchURL(ctx context.Context, rawURL string, opts *fetchOptions) (*FetchResult, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %v", err)
+	}
+
+	if err := validateFetchTarget(ctx, target); err != nil {
+		return nil, err
+	}
+
+	policy, err := checkRobots(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait for our turn on this host (per-host concurrency, min delay, Crawl-delay)
+	release, err := politeness.acquire(ctx, target.Host, policy.crawlDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := doFetch(ctx, rawURL, opts)
+	release()
+	if err != nil {
+		return nil, err
+	}
+
+	// The server asked us to slow down; hold off every request to this host
+	if result.StatusCode == http.StatusTooManyRequests || result.StatusCode == http.StatusServiceUnavailable {
+		politeness.backoff(target.Host, parseRetryAfter(result.Header.Get("Retry-After"), time.Now()))
+	}
+
+	if result.StatusCode == http.StatusNotModified && opts != nil {
+		// Servers may omit validators on 304; keep the ones we sent
+		result.NotModified = true
+		if result.ETag == "" {
+			result.ETag = opts.ETag
+		}
+		if result.LastModified == "" {
+			result.LastModified = opts.LastModified
+		}
+		return result, nil
+	}
+
+	if result.StatusCode != http.StatusOK {
+		return nil, httpStatusError(result.StatusCode)
+	}
+
+	return result, nil
+}
+
+// doFetch performs the HTTP request without robots.txt or status checks
+func doFetch(ctx context.Context, rawURL string, opts *fetchOptions) (*FetchResult, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("User-Agent", crawlerUserAgent)
+
+	if opts != nil {
+		if len(opts.Accept) > 0 {
+			req.Header.Set("Accept", acceptHeader(opts.Accept))
+		}
+		if opts.ETag != "" {
+			req.Header.Set("If-None-Match", opts.ETag)
+		}
+		if opts.LastModified != "" {
+			req.Header.Set("If-Modified-Since", opts.LastModified)
+		}
+	}
+
+	resp, err := crawlerClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL: %w", classifyFetchError(err))
+	}
+	defer resp.Body.Close()
+
+	result := &FetchResult{
+		URL:          resp.Request.URL.String(),
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return result, nil
+	}
+
+	result.Body, err = readLimitedBody(resp.Body, resp.ContentLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", classifyFetchError(err))
+	}
+
+	if opts != nil {
+		if err := checkContentType(resp.Header.Get("Content-Type"), result.Body, opts.Accept); err != nil {
+			return nil, err
+		}
+	}
+
+	// Parsers and content hashes expect UTF-8
+	result.Body = decodeToUTF8(result.Body, resp.Header.Get("Content-Type"))
+
+	return result, nil
+}
+
+// validatorSet tracks HTTP cache validators for the URLs touched by one crawl,
+// along with the crawl's fetch statistics
+type validatorSet struct {
+	stored  map[string]models.FetchValidator
+	touched map[string]models.FetchValidator
+
+	lastStatus      int
+	bytesDownloaded int64
+}
+
+// newValidatorSet loads the validators saved by the previous crawl
+func newValidatorSet(source models.Source) *validatorSet {
+	set := &validatorSet{
+		stored:  map[string]models.FetchValidator{},
+		touched: map[string]models.FetchValidator{},
+	}
+
+	for _, validator := range source.Validators {
+		set.stored[validator.URL] = validator
+	}
+
+	// Older sources only kept validators for the page itself
+	if _, ok := set.stored[source.URL]; !ok && (source.ETag != "" || source.LastModified != "") {
+		set.stored[source.URL] = models.FetchValidator{
+			URL:          source.URL,
+			ETag:         source.ETag,
+			LastModified: source.LastModified,
+		}
+	}
+
+	return set
+}
+
+// fetch performs a conditional GET and records the returned validators
+func (set *validatorSet) fetch(ctx context.Context, rawURL string, accept []string) (*FetchResult, error) {
+	opts := &fetchOptions{Accept: accept}
+	if validator, ok := set.stored[rawURL]; ok {
+		opts.ETag = validator.ETag
+		opts.LastModified = validator.LastModified
+	}
+
+	result, err := fetchURL(ctx, rawURL, opts)
+	if err != nil {
+		var crawlErr *CrawlError
+		if errors.As(err, &crawlErr) && crawlErr.StatusCode != 0 {
+			set.lastStatus = crawlErr.StatusCode
+		}
+		return nil, err
+	}
+
+	set.lastStatus = result.StatusCode
+	set.bytesDownloaded += int64(len(result.Body))
+
+	if result.ETag != "" || result.LastModified != "" {
+		set.touched[rawURL] = models.FetchValidator{
+			URL:          rawURL,
+			ETag:         result.ETag,
+			LastModified: result.LastModified,
+		}
+	} else if !result.NotModified {
+		// The URL stopped sending validators; don't keep offering stale ones
+		delete(set.stored, rawURL)
+	}
+
+	return result, nil
+}
+
+// list returns the stored validators updated with those recorded during this crawl, sorted by URL
+// URLs this crawl didn't fetch (the page when the feed answered, skipped child sitemaps) keep theirs
+func (set *validatorSet) list() []models.FetchValidator {
+	merged := make(map[string]models.FetchValidator, len(set.stored)+len(set.touched))
+	for rawURL, validator := range set.stored {
+		merged[rawURL] = validator
+	}
+	for rawURL, validator := range set.touched {
+		merged[rawURL] = validator
+	}
+
+	validators := make([]models.FetchValidator, 0, len(merged))
+	for _, validator := range merged {
+		validators = append(validators, validator)
+	}
+
+	sort.Slice(validators, func(i, j int) bool {
+		return validators[i].URL < validators[j].URL
+	})
+	return validators
+}
+
+// validatorFor finds the validator saved for a URL
+func validatorFor(validators []models.FetchValidator, rawURL string) models.FetchValidator {
+	for _, validator := range validators {
+		if validator.URL == rawURL {
+			return validator
+		}
+	}
+	return models.FetchValidator{}
+}
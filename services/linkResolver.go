@@ -0,0 +1,49 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// documentBase returns the URL relative links in a page resolve against:
+// the page's final (post-redirect) URL, overridden by a <base href> element
+func documentBase(doc *goquery.Document, pageURL string) *url.URL {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+
+	if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+		if baseHref, err := base.Parse(strings.TrimSpace(href)); err == nil &&
+			(baseHref.Scheme == "http" || baseHref.Scheme == "https") {
+			base = baseHref
+		}
+	}
+
+	return base
+}
+
+// resolveLink turns an href into an absolute http(s) URL
+// Empty, fragment-only and non-http links (javascript:, mailto:, data:) return ""
+func resolveLink(base *url.URL, href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") {
+		return ""
+	}
+
+	ref, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+
+	if base != nil {
+		ref = base.ResolveReference(ref)
+	}
+
+	if (ref.Scheme != "http" && ref.Scheme != "https") || ref.Host == "" {
+		return ""
+	}
+	return ref.String()
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-lang-jwt/helpers"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// articleMetadata is what a page or listing item declares about an article
+type articleMetadata struct {
+	Description string
+	Author      string
+	Image       string
+	Section     string
+	PublishedAt *time.Time
+}
+
+// merge fills the fields still empty with other's values; earlier sources win
+func (meta *articleMetadata) merge(other articleMetadata) {
+	if meta.Description == "" {
+		meta.Description = other.Description
+	}
+	if meta.Author == "" {
+		meta.Author = other.Author
+	}
+	if meta.Image == "" {
+		meta.Image = other.Image
+	}
+	if meta.Section == "" {
+		meta.Section = other.Section
+	}
+	if meta.PublishedAt == nil {
+		meta.PublishedAt = other.PublishedAt
+	}
+}
+
+// applyTo fills the article's empty fields; what the extractor found is kept
+func (meta articleMetadata) applyTo(article *ArticleData) {
+	if article.Summary == "" {
+		article.Summary = truncateSummary(meta.Description)
+	}
+	if article.Author == "" {
+		article.Author = meta.Author
+	}
+	if article.Image == "" {
+		article.Image = meta.Image
+	}
+	if article.Section == "" {
+		article.Section = meta.Section
+	}
+	if article.PublishedAt == nil {
+		article.PublishedAt = meta.PublishedAt
+	}
+}
+
+// pageMetadata reads an article page's metadata, most reliable source first:
+// JSON-LD, h-entry, OpenGraph/Twitter/meta tags, then <time> and rel=author
+func pageMetadata(doc *goquery.Document) articleMetadata {
+	var meta articleMetadata
+
+	meta.merge(jsonLDMetadata(doc.Selection, doc.Url))
+
+	if entry := doc.Find(".h-entry").First(); entry.Length() > 0 {
+		meta.merge(hEntryMetadata(entry, doc.Url))
+	}
+
+	meta.merge(metaTagMetadata(doc, doc.Url))
+	meta.merge(looseMetadata(doc.Find("article").First(), doc.Url))
+	meta.merge(looseMetadata(doc.Selection, doc.Url))
+
+	return meta
+}
+
+// itemMetadata reads the metadata inside one listing item (h-entry, <time>, rel=author)
+func itemMetadata(item *goquery.Selection, base *url.URL) articleMetadata {
+	var meta articleMetadata
+
+	entry := item.Find(".h-entry").First()
+	if item.HasClass("h-entry") {
+		entry = item
+	}
+	if entry.Length() > 0 {
+		meta.merge(hEntryMetadata(entry, base))
+	}
+
+	meta.merge(looseMetadata(item, base))
+	return meta
+}
+
+// jsonLDArticleTypes are the schema.org types treated as articles
+// Subtypes like NewsArticle or BlogPosting match by suffix
+var jsonLDArticleTypes = []string{"Article", "Posting"}
+
+// jsonLDMetadata reads the first schema.org article in the JSON-LD blocks under s
+func jsonLDMetadata(s *goquery.Selection, base *url.URL) articleMetadata {
+	var meta articleMetadata
+
+	s.Find(`script[type="application/ld+json"]`).EachWithBreak(func(i int, script *goquery.Selection) bool {
+		var data interface{}
+		if err := json.Unmarshal([]byte(script.Text()), &data); err != nil {
+			return true
+		}
+
+		node := findJSONLDArticle(data)
+		if node == nil {
+			return true
+		}
+
+		meta = articleMetadata{
+			Description: cleanText(jsonLDString(node["description"], "")),
+			Author:      cleanText(jsonLDString(node["author"], "name")),
+			Image:       resolveLink(base, jsonLDString(node["image"], "url")),
+			Section:     cleanText(jsonLDString(node["articleSection"], "")),
+		}
+
+		meta.PublishedAt = helpers.ParseDate(jsonLDString(node["datePublished"], ""))
+		if meta.PublishedAt == nil {
+			meta.PublishedAt = helpers.ParseDate(jsonLDString(node["dateCreated"], ""))
+		}
+		return false
+	})
+
+	return meta
+}
+
+// findJSONLDArticle walks arrays and @graph lists for the first article node
+func findJSONLDArticle(data interface{}) map[string]interface{} {
+	switch value := data.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if node := findJSONLDArticle(item); node != nil {
+				return node
+			}
+		}
+	case map[string]interface{}:
+		if isJSONLDArticle(value["@type"]) {
+			return value
+		}
+		if graph, ok := value["@graph"]; ok {
+			return findJSONLDArticle(graph)
+		}
+	}
+	return nil
+}
+
+// isJSONLDArticle reports whether @type (a string or a list) names an article type
+func isJSONLDArticle(typeValue interface{}) bool {
+	var types []string
+	switch value := typeValue.(type) {
+	case string:
+		types = []string{value}
+	case []interface{}:
+		for _, item := range value {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+	}
+
+	for _, name := range types {
+		// Accept prefixed forms like "schema:NewsArticle" or "https://schema.org/NewsArticle"
+		if index := strings.LastIndexAny(name, "/:"); index >= 0 {
+			name = name[index+1:]
+		}
+		for _, suffix := range jsonLDArticleTypes {
+			if strings.HasSuffix(name, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLDString returns a JSON-LD value as a string
+// Objects yield their key field (e.g. an author's "name"), lists their first usable entry
+func jsonLDString(value interface{}, key string) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case map[string]interface{}:
+		if key == "" {
+			return ""
+		}
+		return jsonLDString(v[key], "")
+	case []interface{}:
+		for _, item := range v {
+			if text := jsonLDString(item, key); text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+// hEntryMetadata reads the microformats2 properties of an h-entry
+func hEntryMetadata(entry *goquery.Selection, base *url.URL) articleMetadata {
+	meta := articleMetadata{
+		Description: cleanText(entry.Find(".p-summary").First().Text()),
+		Section:     cleanText(entry.Find(".p-category").First().Text()),
+	}
+
+	author := entry.Find(".p-author").First()
+	if name := author.Find(".p-name").First(); name.Length() > 0 {
+		meta.Author = cleanText(name.Text())
+	} else {
+		meta.Author = cleanText(author.Text())
+	}
+
+	photo := entry.Find(".u-photo, .u-featured").First()
+	meta.Image = resolveLink(base, photo.AttrOr("src", photo.AttrOr("href", "")))
+
+	published := entry.Find(".dt-published").First()
+	meta.PublishedAt = helpers.ParseDate(published.AttrOr("datetime", published.AttrOr("title", published.Text())))
+
+	return meta
+}
+
+// metaTagMetadata reads OpenGraph, article:*, Twitter card and plain <meta> tags
+func metaTagMetadata(doc *goquery.Document, base *url.URL) articleMetadata {
+	content := func(selectors ...string) string {
+		for _, selector := range selectors {
+			if value := cleanText(doc.Find(selector).First().AttrOr("content", "")); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	meta := articleMetadata{
+		Description: content(`meta[property="og:description"]`, `meta[name="twitter:description"]`, `meta[name="description"]`),
+		Section:     content(`meta[property="article:section"]`),
+		Image: resolveLink(base, content(
+			`meta[property="og:image"]`,
+			`meta[property="og:image:url"]`,
+			`meta[name="twitter:image"]`,
+			`meta[name="twitter:image:src"]`,
+		)),
+		PublishedAt: helpers.ParseDate(content(
+			`meta[property="article:published_time"]`,
+			`meta[itemprop="datePublished"]`,
+			`meta[name="date"]`,
+		)),
+	}
+
+	// article:author is often a profile URL rather than a name
+	for _, author := range []string{content(`meta[property="article:author"]`), content(`meta[name="author"]`)} {
+		if author != "" && !isHTTPURL(author) {
+			meta.Author = author
+			break
+		}
+	}
+
+	return meta
+}
+
+// looseMetadata reads the first <time datetime> / datePublished and rel=author link under s
+func looseMetadata(s *goquery.Selection, base *url.URL) articleMetadata {
+	var meta articleMetadata
+	if s.Length() == 0 {
+		return meta
+	}
+
+	published := s.Find(`[itemprop="datePublished"], time[datetime]`).First()
+	meta.PublishedAt = helpers.ParseDate(published.AttrOr("datetime", published.AttrOr("content", "")))
+
+	meta.Author = cleanText(s.Find(`a[rel~="author"], [itemprop="author"]`).First().Text())
+
+	return meta
+}
+
+// cleanText collapses whitespace
+func cleanText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// decodeJSON unmarshals a JSON literal the way jsonLDMetadata does
+func decodeJSON(t *testing.T, literal string) interface{} {
+	var value interface{}
+	if err := json.Unmarshal([]byte(literal), &value); err != nil {
+		t.Fatalf("bad test JSON %s: %v", literal, err)
+	}
+	return value
+}
+
+func parseTestPage(t *testing.T, page string) *goquery.Document {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Url, _ = url.Parse("https://news.example/2026/10/story")
+	return doc
+}
+
+func timeAt(t *testing.T, value string) *time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("bad test time %q: %v", value, err)
+	}
+	return &parsed
+}
+
+func TestIsJSONLDArticle(t *testing.T) {
+	articles := []string{`"Article"`, `"NewsArticle"`, `"BlogPosting"`, `"schema:ReportageNewsArticle"`, `"https://schema.org/NewsArticle"`, `["WebPage", "Article"]`}
+	others := []string{`"WebPage"`, `"Organization"`, `"ArticleSeries"`, `["Person", 3]`, `null`, `{"name": "Article"}`}
+
+	for _, literal := range articles {
+		if !isJSONLDArticle(decodeJSON(t, literal)) {
+			t.Errorf("isJSONLDArticle(%s) = false, want true", literal)
+		}
+	}
+	for _, literal := range others {
+		if isJSONLDArticle(decodeJSON(t, literal)) {
+			t.Errorf("isJSONLDArticle(%s) = true, want false", literal)
+		}
+	}
+}
+
+func TestJSONLDString(t *testing.T) {
+	tests := []struct {
+		value string
+		key   string
+		want  string
+	}{
+		{`"  Jane Doe "`, "name", "Jane Doe"},
+		{`{"@type": "Person", "name": "Jane Doe"}`, "name", "Jane Doe"},
+		{`{"@type": "Person", "name": "Jane Doe"}`, "", ""},
+		{`[{"@type": "Person"}, {"name": "Second Author"}]`, "name", "Second Author"},
+		{`["", "https://news.example/a.jpg"]`, "url", "https://news.example/a.jpg"},
+		{`{"url": {"@id": "x"}}`, "url", ""},
+		{`42`, "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := jsonLDString(decodeJSON(t, tt.value), tt.key); got != tt.want {
+			t.Errorf("jsonLDString(%s, %q) = %q, want %q", tt.value, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestFindJSONLDArticle(t *testing.T) {
+	data := decodeJSON(t, `[
+		{"@type": "BreadcrumbList"},
+		{"@context": "https://schema.org", "@graph": [
+			{"@type": "WebSite", "name": "News"},
+			{"@type": ["NewsArticle"], "headline": "Found it"}
+		]}
+	]`)
+
+	node := findJSONLDArticle(data)
+	if node == nil || node["headline"] != "Found it" {
+		t.Fatalf("findJSONLDArticle = %v, want the NewsArticle inside @graph", node)
+	}
+
+	if node := findJSONLDArticle(decodeJSON(t, `{"@graph": [{"@type": "WebPage"}]}`)); node != nil {
+		t.Errorf("findJSONLDArticle without an article = %v", node)
+	}
+}
+
+func TestPageMetadataSources(t *testing.T) {
+	jsonLD := `<script type="application/ld+json">{not json}</script>
+		<script type="application/ld+json">{"@type": "NewsArticle",
+			"description": "From JSON-LD",
+			"author": [{"@type": "Person", "name": "Ld Author"}],
+			"dateCreated": "2026-10-14T08:00:00Z"}</script>`
+	hEntry := `<div class="h-entry">
+			<p class="p-summary">From h-entry</p>
+			<a class="p-category">Politics</a>
+			<span class="p-author h-card"><span class="p-name">Entry Author</span></span>
+			<img class="u-photo" src="/img/entry.jpg">
+			<time class="dt-published" datetime="2026-10-13T08:00:00Z">Monday</time>
+		</div>`
+	metaTags := `<meta property="og:description" content="From OpenGraph">
+		<meta property="og:image" content="/img/og.jpg">
+		<meta property="article:section" content="World">
+		<meta property="article:author" content="https://news.example/staff/jane">
+		<meta name="author" content="Meta Author">
+		<meta property="article:published_time" content="2026-10-12T08:00:00Z">`
+
+	tests := []struct {
+		name string
+		head string
+		body string
+		want articleMetadata
+	}{
+		{
+			name: "json-ld first",
+			head: jsonLD + metaTags,
+			body: hEntry,
+			want: articleMetadata{"From JSON-LD", "Ld Author", "https://news.example/img/entry.jpg", "Politics", timeAt(t, "2026-10-14T08:00:00Z")},
+		},
+		{
+			name: "h-entry before meta tags",
+			head: metaTags,
+			body: hEntry,
+			want: articleMetadata{"From h-entry", "Entry Author", "https://news.example/img/entry.jpg", "Politics", timeAt(t, "2026-10-13T08:00:00Z")},
+		},
+		{
+			name: "meta tags skip a profile URL author",
+			head: metaTags,
+			want: articleMetadata{"From OpenGraph", "Meta Author", "https://news.example/img/og.jpg", "World", timeAt(t, "2026-10-12T08:00:00Z")},
+		},
+		{
+			name: "time and rel=author last",
+			body: `<article><a rel="author" href="/staff/joe">Joe Writer</a><time datetime="2026-10-11T08:00:00Z">Sunday</time></article>`,
+			want: articleMetadata{Author: "Joe Writer", PublishedAt: timeAt(t, "2026-10-11T08:00:00Z")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parseTestPage(t, "<html><head>"+tt.head+"</head><body>"+tt.body+"</body></html>")
+			got := pageMetadata(doc)
+
+			if got.Description != tt.want.Description || got.Author != tt.want.Author || got.Image != tt.want.Image || got.Section != tt.want.Section {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if (got.PublishedAt == nil) != (tt.want.PublishedAt == nil) || (got.PublishedAt != nil && !got.PublishedAt.Equal(*tt.want.PublishedAt)) {
+				t.Errorf("published = %v, want %v", got.PublishedAt, tt.want.PublishedAt)
+			}
+		})
+	}
+}
+
+func TestItemMetadataKeepsExtractedFields(t *testing.T) {
+	doc := parseTestPage(t, `<html><body><li class="h-entry">
+		<a class="u-url" href="/a">Story</a>
+		<span class="p-author">Item Author</span>
+		<p class="p-summary">Item summary</p>
+		<time class="dt-published" title="2026-10-10">last week</time>
+	</li></body></html>`)
+
+	article := ArticleData{Title: "Story", Author: "Listing Author"}
+	itemMetadata(doc.Find("li").First(), doc.Url).applyTo(&article)
+
+	if article.Author != "Listing Author" {
+		t.Errorf("author = %q, want the extractor's value kept", article.Author)
+	}
+	if article.Summary != "Item summary" {
+		t.Errorf("summary = %q", article.Summary)
+	}
+	if article.PublishedAt == nil || !article.PublishedAt.Equal(*timeAt(t, "2026-10-10T00:00:00Z")) {
+		t.Errorf("published = %v", article.PublishedAt)
+	}
+}
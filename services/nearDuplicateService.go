@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-lang-jwt/database"
+	"go-lang-jwt/helpers"
+	"go-lang-jwt/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxNearDuplicateDistance is the largest distance the band lookup is guaranteed to find
+const maxNearDuplicateDistance = helpers.SimHashBandCount - 1
+
+// Near-duplicate settings
+var (
+	// nearDuplicateDistance is the default Hamming distance within which articles are near-duplicates
+	nearDuplicateDistance = helpers.GetEnvInt("NEAR_DUPLICATE_DISTANCE", 6)
+
+	// nearDuplicateCandidates caps how many articles sharing a band are compared
+	nearDuplicateCandidates = helpers.GetEnvInt("NEAR_DUPLICATE_CANDIDATES", 500)
+)
+
+// nearDuplicateDistanceOf returns the source's threshold, or the global one
+// A negative threshold turns near-duplicate detection off
+func nearDuplicateDistanceOf(source models.Source) int {
+	if source.NearDuplicateDistance != nil {
+		return *source.NearDuplicateDistance
+	}
+	return nearDuplicateDistance
+}
+
+// validateNearDuplicateDistance checks a per-source threshold
+func validateNearDuplicateDistance(distance int) error {
+	if distance < -1 || distance > maxNearDuplicateDistance {
+		return fmt.Errorf("invalid near_duplicate_distance: must be between -1 (off) and %d", maxNearDuplicateDistance)
+	}
+	return nil
+}
+
+// findNearDuplicate returns the closest stored article within maxDistance of the fingerprint
+// Candidates are found through the indexed bands, then compared bit by bit
+// When the match is itself a near-duplicate, its original is returned
+func findNearDuplicate(ctx context.Context, fingerprint uint64, maxDistance int) (*primitive.ObjectID, error) {
+	if maxDistance < 0 || fingerprint == 0 {
+		return nil, nil
+	}
+	if maxDistance > maxNearDuplicateDistance {
+		maxDistance = maxNearDuplicateDistance
+	}
+
+	articleCollection := database.OpenCollection(database.Client, "articles")
+
+	opts := options.Find().
+		SetProjection(bson.M{"simhash": 1, "near_duplicate_of": 1}).
+		SetSort(bson.D{{Key: "discovered_at", Value: -1}}).
+		SetLimit(int64(nearDuplicateCandidates))
+
+	cursor, err := articleCollection.Find(ctx, bson.M{
+		"simhash_bands": bson.M{"$in": helpers.SimHashBands(fingerprint)},
+	}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query near-duplicates: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var best *models.Article
+	bestDistance := maxDistance + 1
+	for cursor.Next(ctx) {
+		var candidate models.Article
+		if err := cursor.Decode(&candidate); err != nil {
+			continue
+		}
+
+		distance := helpers.HammingDistance(fingerprint, uint64(candidate.Simhash))
+		if distance < bestDistance {
+			best = &candidate
+			bestDistance = distance
+		}
+	}
+	if err := cursor.Err(); err != nil && !errors.Is(err, context.Canceled) {
+		return nil, fmt.Errorf("failed to read near-duplicates: %v", err)
+	}
+
+	if best == nil {
+		return nil, nil
+	}
+	if best.Near_duplicate_of != nil {
+		return best.Near_duplicate_of, nil
+	}
+	return &best.ID, nil
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-lang-jwt/helpers"
+)
+
+// Per-host politeness settings
+var (
+	maxRequestsPerHost = helpers.GetEnvInt("CRAWLER_MAX_PER_HOST", 2)
+	minHostDelay       = helpers.GetEnvDuration("CRAWLER_MIN_HOST_DELAY", time.Second)
+	maxRetryAfter      = helpers.GetEnvDuration("CRAWLER_MAX_RETRY_AFTER", time.Hour)
+)
+
+// hostState tracks in-flight requests and the earliest next request for one host
+type hostState struct {
+	active      int
+	nextAllowed time.Time
+}
+
+// hostGate spaces out requests to the same host across all crawls in this process
+type hostGate struct {
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+var politeness = &hostGate{hosts: map[string]*hostState{}}
+
+// acquire waits for a free slot on the host and for its minimum delay to pass
+// delay is the host's robots.txt Crawl-delay; the larger of it and minHostDelay applies
+func (gate *hostGate) acquire(ctx context.Context, host string, delay time.Duration) (func(), error) {
+	if delay < minHostDelay {
+		delay = minHostDelay
+	}
+
+	for {
+		gate.mu.Lock()
+		state, ok := gate.hosts[host]
+		if !ok {
+			gate.pruneIdle()
+			state = &hostState{}
+			gate.hosts[host] = state
+		}
+
+		now := time.Now()
+		if state.active < maxRequestsPerHost && !now.Before(state.nextAllowed) {
+			state.active++
+			state.nextAllowed = now.Add(delay)
+			gate.mu.Unlock()
+			return func() { gate.release(host) }, nil
+		}
+
+		// Don't sit out a Retry-After that outlasts the crawl
+		wait := time.Until(state.nextAllowed)
+		if deadline, ok := ctx.Deadline(); ok && state.nextAllowed.After(deadline) {
+			gate.mu.Unlock()
+			return nil, &CrawlError{
+				Code:    CrawlErrorRateLimited,
+				Message: fmt.Sprintf("host %s is rate limited until %s", host, state.nextAllowed.Format(time.RFC3339)),
+			}
+		}
+		gate.mu.Unlock()
+
+		if wait < 50*time.Millisecond {
+			wait = 50 * time.Millisecond
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (gate *hostGate) release(host string) {
+	gate.mu.Lock()
+	defer gate.mu.Unlock()
+
+	state, ok := gate.hosts[host]
+	if !ok {
+		return
+	}
+
+	state.active--
+	if state.active <= 0 && time.Now().After(state.nextAllowed) {
+		delete(gate.hosts, host)
+	}
+}
+
+// pruneIdle drops hosts with no requests in flight and no pending delay
+// Called with gate.mu held
+func (gate *hostGate) pruneIdle() {
+	if len(gate.hosts) < 1024 {
+		return
+	}
+
+	now := time.Now()
+	for host, state := range gate.hosts {
+		if state.active <= 0 && now.After(state.nextAllowed) {
+			delete(gate.hosts, host)
+		}
+	}
+}
+
+// backoff blocks new requests to the host until the given time
+func (gate *hostGate) backoff(host string, until time.Time) {
+	gate.mu.Lock()
+	defer gate.mu.Unlock()
+
+	state, ok := gate.hosts[host]
+	if !ok {
+		state = &hostState{}
+		gate.hosts[host] = state
+	}
+
+	if until.After(state.nextAllowed) {
+		state.nextAllowed = until
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+// Missing or invalid values fall back to the minimum host delay; long ones are capped
+func parseRetryAfter(value string, now time.Time) time.Time {
+	value = strings.TrimSpace(value)
+
+	wait := minHostDelay
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = date.Sub(now)
+	}
+
+	if wait > maxRetryAfter {
+		wait = maxRetryAfter
+	}
+	if wait < minHostDelay {
+		wait = minHostDelay
+	}
+	return now.Add(wait)
+}
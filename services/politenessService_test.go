@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	savedMin, savedMax := minHostDelay, maxRetryAfter
+	minHostDelay, maxRetryAfter = time.Second, time.Hour
+	t.Cleanup(func() { minHostDelay, maxRetryAfter = savedMin, savedMax })
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"120", 2 * time.Minute},
+		{" 30 ", 30 * time.Second},
+		{now.Add(10 * time.Minute).Format(http.TimeFormat), 10 * time.Minute},
+		{now.Add(-time.Minute).Format(http.TimeFormat), time.Second}, // date in the past
+		{"0", time.Second},
+		{"-5", time.Second},
+		{"", time.Second},
+		{"soon", time.Second},
+		{"86400", time.Hour},
+		{now.Add(48 * time.Hour).Format(http.TimeFormat), time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now).Sub(now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) waits %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+// newTestGate returns an empty gate with the given per-host limits
+func newTestGate(t *testing.T, perHost int, minDelay time.Duration) *hostGate {
+	savedPerHost, savedDelay := maxRequestsPerHost, minHostDelay
+	maxRequestsPerHost, minHostDelay = perHost, minDelay
+	t.Cleanup(func() { maxRequestsPerHost, minHostDelay = savedPerHost, savedDelay })
+
+	return &hostGate{hosts: map[string]*hostState{}}
+}
+
+func TestHostGateSpacesRequests(t *testing.T) {
+	gate := newTestGate(t, 2, 60*time.Millisecond)
+	ctx := context.Background()
+
+	release, err := gate.acquire(ctx, "news.example", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	// Another host isn't held up
+	start := time.Now()
+	release, err = gate.acquire(ctx, "other.example", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if waited := time.Since(start); waited > 30*time.Millisecond {
+		t.Errorf("unrelated host waited %s", waited)
+	}
+
+	// The same host waits out the minimum delay even though no request is in flight
+	start = time.Now()
+	release, err = gate.acquire(ctx, "news.example", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if waited := time.Since(start); waited < 50*time.Millisecond {
+		t.Errorf("second request to the host waited %s, want about 60ms", waited)
+	}
+
+	// A Crawl-delay longer than the minimum wins
+	release, err = gate.acquire(ctx, "slow.example", 150*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	start = time.Now()
+	release, err = gate.acquire(ctx, "slow.example", 150*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if waited := time.Since(start); waited < 130*time.Millisecond {
+		t.Errorf("request after a 150ms Crawl-delay waited %s", waited)
+	}
+}
+
+func TestHostGateLimitsConcurrency(t *testing.T) {
+	gate := newTestGate(t, 1, 0)
+
+	release, err := gate.acquire(context.Background(), "news.example", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 80*time.Millisecond)
+	defer cancel()
+	if _, err := gate.acquire(ctx, "news.example", 0); err != context.DeadlineExceeded {
+		t.Errorf("acquire with the only slot taken = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	release()
+	release, err = gate.acquire(context.Background(), "news.example", 0)
+	if err != nil {
+		t.Fatalf("acquire after release: %v", err)
+	}
+	release()
+}
+
+func TestHostGateBackoff(t *testing.T) {
+	gate := newTestGate(t, 2, 0)
+
+	gate.backoff("news.example", time.Now().Add(time.Hour))
+
+	// A Retry-After past the caller's deadline fails at once instead of waiting
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := gate.acquire(ctx, "news.example", 0)
+	if got := crawlErrorCode(err); got != CrawlErrorRateLimited {
+		t.Errorf("acquire during backoff code = %q (%v), want %q", got, err, CrawlErrorRateLimited)
+	}
+	if waited := time.Since(start); waited > 100*time.Millisecond {
+		t.Errorf("acquire during backoff waited %s", waited)
+	}
+
+	// A short backoff is waited out
+	gate.backoff("short.example", time.Now().Add(80*time.Millisecond))
+	start = time.Now()
+	release, err := gate.acquire(ctx, "short.example", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if waited := time.Since(start); waited < 60*time.Millisecond {
+		t.Errorf("acquire after an 80ms backoff waited %s", waited)
+	}
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go-lang-jwt/models"
+)
+
+// PreviewRequest describes a dry-run extraction
+type PreviewRequest struct {
+	URL       string                  `json:"url" binding:"required"`
+	Rules     *models.ExtractionRules `json:"rules"`
+	Strategy  string                  `json:"strategy"`  // feed, sitemap or html; empty picks automatically
+	Extractor string                  `json:"extractor"` // force one HTML extractor by name
+}
+
+// PreviewResult is what a source would produce if crawled now
+type PreviewResult struct {
+	URL        string        `json:"url"`
+	RSSUrl     string        `json:"rss_url"`
+	SitemapUrl string        `json:"sitemap_url"`
+	Strategy   string        `json:"strategy"`
+	Extractor  string        `json:"extractor"`
+	Articles   []ArticleData `json:"articles"`
+	Warnings   []string      `json:"warnings"`
+}
+
+// PreviewExtraction runs discovery and the crawl's fetch and extraction pipeline
+// without reading or writing sources or articles
+func PreviewExtraction(ctx context.Context, req PreviewRequest) (*PreviewResult, error) {
+	// Step 1: Validate the request
+	parsedURL, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return nil, errors.New("invalid URL format")
+	}
+	if err := ValidateSourceURL(ctx, parsedURL); err != nil {
+		return nil, err
+	}
+
+	switch req.Strategy {
+	case "", strategyFeed, strategySitemap, strategyHTML:
+	default:
+		return nil, fmt.Errorf("invalid strategy: %s", req.Strategy)
+	}
+
+	if req.Extractor != "" && !extractorExists(req.Extractor) {
+		return nil, fmt.Errorf("invalid extractor: %s", req.Extractor)
+	}
+
+	if req.Rules != nil {
+		if _, err := compileExtractionRules(req.Rules); err != nil {
+			return nil, fmt.Errorf("invalid extraction rules: %v", err)
+		}
+	}
+
+	// Step 2: Build the source a subscription would create
+	source := models.Source{
+		URL:             parsedURL.String(),
+		Name:            parsedURL.Host,
+		ExtractionRules: req.Rules,
+	}
+
+	preview := &PreviewResult{URL: source.URL}
+
+	discovery, err := DiscoverSource(ctx, source.URL)
+	if err != nil {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("discovery failed: %v", err))
+	} else {
+		applyDiscovery(&source, discovery)
+	}
+	preview.RSSUrl = source.RSSUrl
+	preview.SitemapUrl = source.SitemapUrl
+
+	// Step 3: Extract without cache validators so the full content comes back
+	result, err := runExtraction(ctx, source, newValidatorSet(models.Source{}), extractOptions{
+		strategy:  req.Strategy,
+		extractor: req.Extractor,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("extraction failed: %w", err)
+	}
+
+	preview.Strategy = result.Strategy
+	preview.Extractor = result.Extractor
+	preview.Articles = result.Articles
+	preview.Warnings = append(preview.Warnings, result.Warnings...)
+
+	missingDates := 0
+	for _, article := range result.Articles {
+		if article.PublishedAt == nil {
+			missingDates++
+		}
+	}
+	if missingDates > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d of %d articles have no publication date", missingDates, len(result.Articles)))
+	}
+
+	if preview.Articles == nil {
+		preview.Articles = []ArticleData{}
+	}
+	if preview.Warnings == nil {
+		preview.Warnings = []string{}
+	}
+
+	return preview, nil
+}
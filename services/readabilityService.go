@@ -0,0 +1,314 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"go-lang-jwt/helpers"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Readability settings
+var (
+	readingWordsPerMinute = helpers.GetEnvInt("READING_WORDS_PER_MINUTE", 200)
+	maxContentBytes       = helpers.GetEnvInt("ARTICLE_CONTENT_MAX_BYTES", 512*1024)
+)
+
+// minContentChars is the shortest text accepted as an article's content
+const minContentChars = 250
+
+// articleContent is the main text of an article page
+type articleContent struct {
+	HTML           string // sanitized markup
+	Text           string // plain text, paragraphs separated by blank lines
+	WordCount      int
+	ReadingMinutes int
+}
+
+var (
+	// Boilerplate: classes and ids of elements that are rarely the article
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumb|comment|community|cookie|disqus|footer|header|menu|modal|nav|newsletter|pager|pagination|popup|promo|related|remark|share|shoutbox|sidebar|social|sponsor|subscribe|tags|tool|widget|\bad\b|ad-|advert`)
+	likelyCandidates   = regexp.MustCompile(`(?i)article|body|content|entry|main|page|post|story|text|blog`)
+
+	// Elements that never hold article text
+	boilerplateElements = "script, style, noscript, template, iframe, object, embed, form, button, input, select, textarea, nav, header, footer, aside, svg, canvas, link, meta"
+)
+
+// Tags kept in the sanitized HTML; everything else is unwrapped to its children
+var allowedContentTags = map[string]bool{
+	"p": true, "br": true, "hr": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "li": true, "dl": true, "dt": true, "dd": true,
+	"blockquote": true, "pre": true, "code": true, "em": true, "strong": true, "b": true, "i": true,
+	"sub": true, "sup": true, "a": true, "img": true, "figure": true, "figcaption": true,
+	"table": true, "thead": true, "tbody": true, "tr": true, "th": true, "td": true,
+}
+
+// Tags dropped with their content even when boilerplate removal missed them
+var droppedContentTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "iframe": true, "frame": true,
+	"object": true, "embed": true, "applet": true, "svg": true, "math": true, "canvas": true,
+	"textarea": true, "select": true, "button": true, "link": true, "meta": true, "base": true,
+}
+
+// Tags that end a line in the plain text
+var blockContentTags = map[string]bool{
+	"p": true, "br": true, "hr": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"li": true, "dt": true, "dd": true, "blockquote": true, "pre": true, "figcaption": true, "tr": true,
+}
+
+// extractReadableContent finds the page's main content, readability style:
+// strip boilerplate, score paragraphs' containers, keep the best one and its related siblings
+// The document is modified
+func extractReadableContent(doc *goquery.Document) (*articleContent, error) {
+	// Step 1: Remove elements that are never content, and likely boilerplate blocks
+	doc.Find(boilerplateElements).Remove()
+	doc.Find("body *").Each(func(i int, s *goquery.Selection) {
+		if s.Is("article, main, body") {
+			return
+		}
+		hint := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+		if unlikelyCandidates.MatchString(hint) && !likelyCandidates.MatchString(hint) {
+			s.Remove()
+		}
+	})
+
+	// Step 2: Score the containers of every paragraph
+	top := topCandidate(doc)
+	if top == nil {
+		return nil, errors.New("no readable content found")
+	}
+
+	// Step 3: Render the candidate (and siblings that look like content) as sanitized HTML
+	nodes := []*html.Node{top.node}
+	if parent := top.node.Parent; parent != nil {
+		nodes = nil
+		threshold := math.Max(10, top.score*0.2)
+		for sibling := parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+			if sibling == top.node || (sibling.Type == html.ElementNode && contentScore(sibling) >= threshold) {
+				nodes = append(nodes, sibling)
+			}
+		}
+	}
+
+	var builder contentBuilder
+	builder.base = doc.Url
+	for _, node := range nodes {
+		builder.render(node)
+	}
+
+	content := &articleContent{
+		HTML: strings.TrimSpace(builder.html.String()),
+		Text: normalizeContentText(builder.text.String()),
+	}
+	if len(content.Text) < minContentChars {
+		return nil, errors.New("no readable content found")
+	}
+	if len(content.HTML) > maxContentBytes {
+		return nil, errors.New("article content too large")
+	}
+
+	content.WordCount = len(strings.Fields(content.Text))
+	content.ReadingMinutes = readingTime(content.WordCount)
+	return content, nil
+}
+
+// scoredNode is a candidate container and its score
+type scoredNode struct {
+	node  *html.Node
+	score float64
+}
+
+// topCandidate scores paragraph containers: each paragraph gives points for its length and commas
+// to its parent and half as much to its grandparent; link-heavy containers are penalized
+func topCandidate(doc *goquery.Document) *scoredNode {
+	scores := make(map[*html.Node]float64)
+
+	doc.Find("p, pre, td, blockquote").Each(func(i int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		if len(text) < 25 {
+			return
+		}
+
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text)/100), 3)
+
+		parent := s.Parent()
+		if parent.Length() == 0 {
+			return
+		}
+		addCandidateScore(scores, parent, score)
+		if grandparent := parent.Parent(); grandparent.Length() > 0 {
+			addCandidateScore(scores, grandparent, score/2)
+		}
+	})
+
+	var best *scoredNode
+	for node, score := range scores {
+		score *= 1 - linkDensity(goquery.NewDocumentFromNode(node).Selection)
+		if best == nil || score > best.score {
+			best = &scoredNode{node: node, score: score}
+		}
+	}
+	return best
+}
+
+// addCandidateScore adds points to a container, seeding it from its tag and class/id the first time
+func addCandidateScore(scores map[*html.Node]float64, s *goquery.Selection, points float64) {
+	node := s.Get(0)
+	if _, seen := scores[node]; !seen {
+		scores[node] = initialScore(s)
+	}
+	scores[node] += points
+}
+
+// initialScore weights a container by its tag and class/id hints
+func initialScore(s *goquery.Selection) float64 {
+	score := 0.0
+	switch goquery.NodeName(s) {
+	case "article":
+		score += 10
+	case "div", "main", "section":
+		score += 5
+	case "pre", "td", "blockquote":
+		score += 3
+	case "ol", "ul", "dl", "form", "li":
+		score -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score -= 5
+	}
+
+	hint := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+	if likelyCandidates.MatchString(hint) {
+		score += 25
+	}
+	if unlikelyCandidates.MatchString(hint) {
+		score -= 25
+	}
+	return score
+}
+
+// contentScore scores a sibling of the top candidate by its own text
+func contentScore(node *html.Node) float64 {
+	s := goquery.NewDocumentFromNode(node).Selection
+	text := strings.TrimSpace(s.Text())
+	if len(text) < 80 {
+		return 0
+	}
+	return (initialScore(s) + float64(len(text)/100) + float64(strings.Count(text, ","))) * (1 - linkDensity(s))
+}
+
+// linkDensity is the share of the text inside links
+func linkDensity(s *goquery.Selection) float64 {
+	total := len(strings.TrimSpace(s.Text()))
+	if total == 0 {
+		return 0
+	}
+
+	linked := 0
+	s.Find("a").Each(func(i int, link *goquery.Selection) {
+		linked += len(strings.TrimSpace(link.Text()))
+	})
+	return math.Min(float64(linked)/float64(total), 1)
+}
+
+// contentBuilder writes sanitized HTML and plain text side by side
+type contentBuilder struct {
+	base *url.URL
+	html strings.Builder
+	text strings.Builder
+}
+
+// render writes node and its children, keeping only allowed tags and attributes
+func (b *contentBuilder) render(node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		b.html.WriteString(html.EscapeString(node.Data))
+		b.text.WriteString(node.Data)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	tag := node.Data
+	if tag == "h1" {
+		return // the article title, stored separately
+	}
+	if droppedContentTags[tag] {
+		return
+	}
+
+	if !allowedContentTags[tag] {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			b.render(child)
+		}
+		if tag == "div" || tag == "section" {
+			b.text.WriteString("\n\n")
+		}
+		return
+	}
+
+	switch tag {
+	case "img":
+		if src := resolveLink(b.base, attrValue(node, "src")); src != "" {
+			b.html.WriteString(`<img src="` + html.EscapeString(src) + `" alt="` + html.EscapeString(attrValue(node, "alt")) + `">`)
+		}
+		return
+	case "br", "hr":
+		b.html.WriteString("<" + tag + ">")
+		b.text.WriteString("\n")
+		return
+	case "a":
+		if href := resolveLink(b.base, attrValue(node, "href")); href != "" {
+			b.html.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow noopener">`)
+		} else {
+			b.html.WriteString("<a>")
+		}
+	default:
+		b.html.WriteString("<" + tag + ">")
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		b.render(child)
+	}
+
+	b.html.WriteString("</" + tag + ">")
+	if blockContentTags[tag] {
+		b.text.WriteString("\n\n")
+	} else if tag == "td" || tag == "th" {
+		b.text.WriteString("\t")
+	}
+}
+
+// attrValue returns an attribute of an element, or ""
+func attrValue(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return strings.TrimSpace(attr.Val)
+		}
+	}
+	return ""
+}
+
+// normalizeContentText collapses whitespace within paragraphs and drops empty ones
+func normalizeContentText(text string) string {
+	var paragraphs []string
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if paragraph = strings.Join(strings.Fields(paragraph), " "); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// readingTime estimates minutes to read, rounded up
+func readingTime(words int) int {
+	if words == 0 || readingWordsPerMinute <= 0 {
+		return 0
+	}
+	return (words + readingWordsPerMinute - 1) / readingWordsPerMinute
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// sanitize renders the body of an HTML fragment through the content sanitizer
+func sanitize(t *testing.T, fragment string) (string, string) {
+	doc, err := html.Parse(strings.NewReader("<html><body>" + fragment + "</body></html>"))
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", fragment, err)
+	}
+
+	var body *html.Node
+	var find func(node *html.Node)
+	find = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.Data == "body" {
+			body = node
+			return
+		}
+		for child := node.FirstChild; child != nil && body == nil; child = child.NextSibling {
+			find(child)
+		}
+	}
+	find(doc)
+
+	builder := contentBuilder{base: &url.URL{Scheme: "https", Host: "example.com", Path: "/news/"}}
+	for child := body.FirstChild; child != nil; child = child.NextSibling {
+		builder.render(child)
+	}
+	return builder.html.String(), normalizeContentText(builder.text.String())
+}
+
+func TestContentSanitizerTags(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"formatting kept", `<p>Hello <b>bold</b> <em>and</em> <code>x</code></p>`, `<p>Hello <b>bold</b> <em>and</em> <code>x</code></p>`},
+		{"lists and quotes kept", `<ul><li>one</li></ul><blockquote>q</blockquote>`, `<ul><li>one</li></ul><blockquote>q</blockquote>`},
+		{"attributes stripped", `<p class="lead" style="color:red" onclick="steal()" id="p1">Hi</p>`, `<p>Hi</p>`},
+		{"unknown tags unwrapped", `<div><span title="t">text</span></div>`, `text`},
+		{"title dropped", `<h1>Title</h1><h2>Sub</h2>`, `<h2>Sub</h2>`},
+		{"script dropped", `<script>alert(1)</script><p>ok</p>`, `<p>ok</p>`},
+		{"style dropped", `<style>p { color: red }</style><p>ok</p>`, `<p>ok</p>`},
+		{"iframe dropped", `<iframe src="https://evil.example/"></iframe><p>ok</p>`, `<p>ok</p>`},
+		{"object and embed dropped", `<object data="x.swf"><embed src="x.swf"></object>ok`, `ok`},
+		{"svg dropped", `<svg><a href="https://evil.example/"><text>t</text></a></svg>ok`, `ok`},
+		{"form controls dropped", `<form><textarea>t</textarea><button>b</button>text</form>`, `text`},
+		{"text escaped", `<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>`, `<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>`},
+	}
+
+	for _, tt := range tests {
+		if got, _ := sanitize(t, tt.in); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestContentSanitizerURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"relative link resolved", `<a href="/story">x</a>`, `<a href="https://example.com/story" rel="nofollow noopener">x</a>`},
+		{"absolute link kept", `<a href="http://other.example/a?b=1&c=2">x</a>`, `<a href="http://other.example/a?b=1&amp;c=2" rel="nofollow noopener">x</a>`},
+		{"javascript link dropped", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"mixed-case javascript link dropped", `<a href=" JaVaScRiPt:alert(1)">x</a>`, `<a>x</a>`},
+		{"vbscript link dropped", `<a href="vbscript:msgbox(1)">x</a>`, `<a>x</a>`},
+		{"data link dropped", `<a href="data:text/html;base64,PHNjcmlwdD4=">x</a>`, `<a>x</a>`},
+		{"mailto link dropped", `<a href="mailto:a@example.com">x</a>`, `<a>x</a>`},
+		{"fragment link dropped", `<a href="#top">x</a>`, `<a>x</a>`},
+		{"link attributes stripped", `<a href="/a" target="_blank" onmouseover="x()">x</a>`, `<a href="https://example.com/a" rel="nofollow noopener">x</a>`},
+		{"relative image resolved", `<img src="pic.jpg" alt="A &quot;pic&quot;" onerror="x()">`, `<img src="https://example.com/news/pic.jpg" alt="A &#34;pic&#34;">`},
+		{"javascript image dropped", `<img src="javascript:alert(1)">`, ``},
+		{"data image dropped", `<img src="data:image/svg+xml;base64,PHN2Zz4=">`, ``},
+		{"image without src dropped", `<img alt="x">`, ``},
+	}
+
+	for _, tt := range tests {
+		if got, _ := sanitize(t, tt.in); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestContentSanitizerText(t *testing.T) {
+	_, text := sanitize(t, `<h1>Title</h1><p>First   paragraph,
+		wrapped.</p><script>var x = 1;</script><ul><li>one</li><li>two</li></ul><p>Last<br>line</p>`)
+
+	want := "First paragraph, wrapped.\n\none\n\ntwo\n\nLast line"
+	if text != want {
+		t.Errorf("got %q, want %q", text, want)
+	}
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-lang-jwt/database"
+	"go-lang-jwt/helpers"
+	"go-lang-jwt/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Global retention policy; sources can override each field
+var (
+	retentionMaxArticles = helpers.GetEnvInt("RETENTION_MAX_ARTICLES", 1000)
+	retentionMaxAgeDays  = helpers.GetEnvInt("RETENTION_MAX_AGE_DAYS", 0)
+	retentionKeepSaved   = helpers.GetEnvInt("RETENTION_KEEP_SAVED", 1) == 1
+)
+
+// Sweeper settings
+var (
+	retentionSweeperEnabled = helpers.GetEnvInt("RETENTION_SWEEPER_ENABLED", 1) == 1
+	retentionSweepInterval  = helpers.GetEnvDuration("RETENTION_SWEEP_INTERVAL", time.Hour)
+	retentionSweepBatch     = helpers.GetEnvInt("RETENTION_SWEEP_BATCH", 1000)
+)
+
+// retentionPolicy is the effective policy for one source
+type retentionPolicy struct {
+	maxArticles int           // 0 keeps any number
+	maxAge      time.Duration // 0 keeps articles forever
+	keepSaved   bool
+}
+
+// retentionPolicyOf merges the source's overrides into the global policy
+func retentionPolicyOf(source models.Source) retentionPolicy {
+	policy := retentionPolicy{
+		maxArticles: retentionMaxArticles,
+		maxAge:      time.Duration(retentionMaxAgeDays) * 24 * time.Hour,
+		keepSaved:   retentionKeepSaved,
+	}
+
+	if overrides := source.Retention; overrides != nil {
+		if overrides.MaxArticles != nil {
+			policy.maxArticles = *overrides.MaxArticles
+		}
+		if overrides.MaxAgeDays != nil {
+			policy.maxAge = time.Duration(*overrides.MaxAgeDays) * 24 * time.Hour
+		}
+		if overrides.KeepSaved != nil {
+			policy.keepSaved = *overrides.KeepSaved
+		}
+	}
+	return policy
+}
+
+// validateRetentionPolicy checks per-source overrides
+func validateRetentionPolicy(policy *models.RetentionPolicy) error {
+	if policy.MaxArticles != nil && *policy.MaxArticles < 0 {
+		return errors.New("invalid retention: max_articles must be 0 (no limit) or more")
+	}
+	if policy.MaxAgeDays != nil && *policy.MaxAgeDays < 0 {
+		return errors.New("invalid retention: max_age_days must be 0 (no limit) or more")
+	}
+	return nil
+}
+
+// StartRetentionSweeper periodically deletes articles the retention policies no longer keep
+func StartRetentionSweeper(ctx context.Context) {
+	if !retentionSweeperEnabled {
+		log.Println("Retention sweeper disabled")
+		return
+	}
+
+	log.Printf("Starting retention sweeper (every %s)", retentionSweepInterval)
+
+	go func() {
+		ticker := time.NewTicker(retentionSweepInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := sweepRetention(ctx); err != nil {
+				log.Printf("Retention sweeper: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// sweepRetention applies each source's retention policy
+func sweepRetention(ctx context.Context) error {
+	sourceCollection := database.OpenCollection(database.Client, "sources")
+
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "url": 1, "retention": 1})
+	cursor, err := sourceCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return fmt.Errorf("failed to list sources: %v", err)
+	}
+
+	var sources []models.Source
+	if err := cursor.All(ctx, &sources); err != nil {
+		return fmt.Errorf("failed to decode sources: %v", err)
+	}
+
+	for _, source := range sources {
+		sourceCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		deleted, released, err := applyRetention(sourceCtx, source.ID, retentionPolicyOf(source))
+		cancel()
+
+		if err != nil {
+			log.Printf("Retention sweeper: %s: %v", source.URL, err)
+			continue
+		}
+		if deleted > 0 || released > 0 {
+			log.Printf("Retention sweeper: deleted %d articles from %s, unlinked %d still carried by other sources", deleted, source.URL, released)
+		}
+	}
+
+	return nil
+}
+
+// applyRetention removes a source's articles beyond its count or age limit, at most one batch per sweep
+// An article counts toward the limits of every source carrying it (source_id or source_ids);
+// one another source still carries is only unlinked from this source, and deleted once no carrier is left
+// Articles a user starred or saved are kept when the policy says so
+func applyRetention(ctx context.Context, sourceID primitive.ObjectID, policy retentionPolicy) (int, int, error) {
+	articleCollection := database.OpenCollection(database.Client, "articles")
+
+	carried := []bson.M{{"source_ids": sourceID}, {"source_id": sourceID}}
+
+	// Step 1: Articles beyond the newest maxArticles, and articles older than maxAge
+	// Starred and saved articles are skipped in the query, so a run of them past the cutoff
+	// can't fill every batch and hide the older articles behind it
+	seen := make(map[primitive.ObjectID]bool)
+	var expired []models.Article
+	collect := func(filter bson.M, sort bson.D, skip int) error {
+		pipeline := mongo.Pipeline{
+			{{Key: "$match", Value: filter}},
+			{{Key: "$sort", Value: sort}},
+		}
+		if skip > 0 {
+			pipeline = append(pipeline, bson.D{{Key: "$skip", Value: skip}})
+		}
+		if policy.keepSaved {
+			pipeline = append(pipeline,
+				bson.D{{Key: "$lookup", Value: bson.M{
+					"from":         "saved_articles",
+					"localField":   "_id",
+					"foreignField": "article_id",
+					"as":           "saved",
+				}}},
+				bson.D{{Key: "$match", Value: bson.M{"saved": bson.M{"$size": 0}}}},
+			)
+		}
+		pipeline = append(pipeline,
+			bson.D{{Key: "$limit", Value: retentionSweepBatch}},
+			bson.D{{Key: "$project", Value: bson.M{"_id": 1, "source_id": 1, "source_ids": 1}}},
+		)
+
+		cursor, err := articleCollection.Aggregate(ctx, pipeline)
+		if err != nil {
+			return fmt.Errorf("failed to find expired articles: %v", err)
+		}
+
+		var articles []models.Article
+		if err := cursor.All(ctx, &articles); err != nil {
+			return fmt.Errorf("failed to decode expired articles: %v", err)
+		}
+		for _, article := range articles {
+			if !seen[article.ID] {
+				seen[article.ID] = true
+				expired = append(expired, article)
+			}
+		}
+		return nil
+	}
+
+	if policy.maxArticles > 0 {
+		sort := bson.D{{Key: "discovered_at", Value: -1}, {Key: "_id", Value: -1}}
+		if err := collect(bson.M{"$or": carried}, sort, policy.maxArticles); err != nil {
+			return 0, 0, err
+		}
+	}
+	if policy.maxAge > 0 {
+		filter := bson.M{
+			"$or":           carried,
+			"discovered_at": bson.M{"$lt": time.Now().Add(-policy.maxAge)},
+		}
+		if err := collect(filter, bson.D{{Key: "discovered_at", Value: 1}}, 0); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	// Step 2: Unlink articles other sources still carry, delete the rest
+	var sharedIDs, articleIDs []primitive.ObjectID
+	for _, article := range expired {
+		if carriedElsewhere(article, sourceID) {
+			sharedIDs = append(sharedIDs, article.ID)
+		} else {
+			articleIDs = append(articleIDs, article.ID)
+		}
+	}
+
+	released, err := unlinkArticles(ctx, sourceID, sharedIDs)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(articleIDs) == 0 {
+		return 0, released, nil
+	}
+
+	// Step 3: Delete the articles with their revisions and stars
+	result, err := articleCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": articleIDs}})
+	if err != nil {
+		return 0, released, fmt.Errorf("failed to delete articles: %v", err)
+	}
+
+	for _, name := range []string{"article_revisions", "saved_articles"} {
+		collection := database.OpenCollection(database.Client, name)
+		if _, err := collection.DeleteMany(ctx, bson.M{"article_id": bson.M{"$in": articleIDs}}); err != nil {
+			log.Printf("Retention sweeper: failed to clean up %s: %v", name, err)
+		}
+	}
+
+	return int(result.DeletedCount), released, nil
+}
+
+// carriedElsewhere reports whether a source other than sourceID carries the article
+func carriedElsewhere(article models.Article, sourceID primitive.ObjectID) bool {
+	if article.Source_id != sourceID {
+		return true
+	}
+	for _, id := range article.Source_ids {
+		if id != sourceID {
+			return true
+		}
+	}
+	return false
+}
+
+// unlinkArticles removes sourceID from the articles' sources and mentions
+// When it owned an article, the first remaining source takes over
+func unlinkArticles(ctx context.Context, sourceID primitive.ObjectID, articleIDs []primitive.ObjectID) (int, error) {
+	if len(articleIDs) == 0 {
+		return 0, nil
+	}
+
+	articleCollection := database.OpenCollection(database.Client, "articles")
+
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"source_ids": bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$source_ids", bson.A{"$source_id"}}},
+				"cond":  bson.M{"$ne": bson.A{"$$this", sourceID}},
+			}},
+			"mentions": bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$mentions", bson.A{}}},
+				"cond":  bson.M{"$ne": bson.A{"$$this.source_id", sourceID}},
+			}},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"source_id": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$source_id", sourceID}},
+				bson.M{"$arrayElemAt": bson.A{"$source_ids", 0}},
+				"$source_id",
+			}},
+		}}},
+	}
+
+	// Only articles another source still carries, in case that changed since they were read
+	result, err := articleCollection.UpdateMany(ctx, bson.M{
+		"_id": bson.M{"$in": articleIDs},
+		"$or": []bson.M{
+			{"source_id": bson.M{"$ne": sourceID}},
+			{"source_ids": bson.M{"$elemMatch": bson.M{"$ne": sourceID}}},
+		},
+	}, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to unlink articles: %v", err)
+	}
+
+	return int(result.ModifiedCount), nil
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-lang-jwt/helpers"
+)
+
+// robotsAgentToken is the product token matched against User-agent lines
+const robotsAgentToken = "FeedAggregator"
+
+// robotsMaxBytes is the largest robots.txt we parse (RFC 9309 minimum is 500 KiB)
+const robotsMaxBytes = 512 * 1024
+
+// robotsCacheTTL controls how long a host's robots.txt is reused
+var robotsCacheTTL = helpers.GetEnvDuration("ROBOTS_CACHE_TTL", 24*time.Hour)
+
+// robotsCacheMaxHosts bounds how many hosts' robots.txt are kept in memory
+const robotsCacheMaxHosts = 1024
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+	regex   *regexp.Regexp
+}
+
+type robotsGroup struct {
+	agents     []string
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+// robotsPolicy is the parsed robots.txt of one host, reduced to our agent
+type robotsPolicy struct {
+	robotsURL  string
+	rules      []robotsRule
+	crawlDelay time.Duration
+	sitemaps   []string
+}
+
+type robotsCacheEntry struct {
+	policy    *robotsPolicy
+	expiresAt time.Time
+}
+
+var (
+	robotsCache   = map[string]robotsCacheEntry{}
+	robotsCacheMu sync.Mutex
+)
+
+// checkRobots refuses URLs that the host's robots.txt disallows for our agent
+func checkRobots(ctx context.Context, target *url.URL) (*robotsPolicy, error) {
+	policy, err := robotsFor(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if !policy.allows(target) {
+		return nil, &CrawlError{
+			Code:    CrawlErrorBlockedByRobots,
+			Message: fmt.Sprintf("%s disallows %s for %s", policy.robotsURL, target.RequestURI(), robotsAgentToken),
+		}
+	}
+
+	return policy, nil
+}
+
+// robotsFor returns the cached robots.txt policy for a URL's host
+func robotsFor(ctx context.Context, target *url.URL) (*robotsPolicy, error) {
+	key := target.Scheme + "://" + target.Host
+
+	robotsCacheMu.Lock()
+	entry, ok := robotsCache[key]
+	robotsCacheMu.Unlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.policy, nil
+	}
+
+	// robots.txt counts against the host's politeness budget like any other request
+	release, err := politeness.acquire(ctx, target.Host, 0)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := fetchRobots(ctx, key+"/robots.txt")
+	release()
+	if err != nil {
+		return nil, err
+	}
+
+	robotsCacheMu.Lock()
+	storeRobots(key, policy, time.Now())
+	robotsCacheMu.Unlock()
+
+	return policy, nil
+}
+
+// storeRobots caches a host's policy, keeping the cache under robotsCacheMaxHosts
+// Expired entries are swept first; if none expired an arbitrary host is evicted
+// Called with robotsCacheMu held
+func storeRobots(key string, policy *robotsPolicy, now time.Time) {
+	if _, ok := robotsCache[key]; !ok && len(robotsCache) >= robotsCacheMaxHosts {
+		for host, entry := range robotsCache {
+			if !now.Before(entry.expiresAt) {
+				delete(robotsCache, host)
+			}
+		}
+		for host := range robotsCache {
+			if len(robotsCache) < robotsCacheMaxHosts {
+				break
+			}
+			delete(robotsCache, host)
+		}
+	}
+
+	robotsCache[key] = robotsCacheEntry{policy: policy, expiresAt: now.Add(robotsCacheTTL)}
+}
+
+// fetchRobots downloads and parses a robots.txt file
+// The caller holds a politeness slot for the host; fetchURL can't be used as it checks robots.txt itself
+// Missing files (4xx) allow everything; server errors are reported so the crawl is retried later
+func fetchRobots(ctx context.Context, robotsURL string) (*robotsPolicy, error) {
+	result, err := doFetch(ctx, robotsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch robots.txt: %w", err)
+	}
+
+	if result.StatusCode >= 400 && result.StatusCode < 500 {
+		return &robotsPolicy{robotsURL: robotsURL}, nil
+	}
+	if result.StatusCode != http.StatusOK {
+		return nil, &CrawlError{
+			Code:       CrawlErrorServerError,
+			Message:    fmt.Sprintf("robots.txt unavailable: status %d", result.StatusCode),
+			StatusCode: result.StatusCode,
+		}
+	}
+
+	body := result.Body
+	if len(body) > robotsMaxBytes {
+		body = body[:robotsMaxBytes]
+	}
+
+	policy := parseRobots(body, robotsAgentToken)
+	policy.robotsURL = robotsURL
+	return policy, nil
+}
+
+// parseRobots parses robots.txt and keeps the groups that apply to agent
+// Groups naming the agent win over "*"; multiple matching groups are merged
+func parseRobots(body []byte, agent string) *robotsPolicy {
+	policy := &robotsPolicy{}
+	agent = strings.ToLower(agent)
+
+	var groups []*robotsGroup
+	var current *robotsGroup
+	lastWasAgent := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if current == nil || !lastWasAgent {
+				current = &robotsGroup{}
+				groups = append(groups, current)
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			lastWasAgent = true
+			continue
+
+		case "allow", "disallow":
+			// An empty Disallow means "allow everything"
+			if current != nil && value != "" {
+				current.rules = append(current.rules, newRobotsRule(key == "allow", value))
+			}
+
+		case "crawl-delay":
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && current != nil && seconds > 0 {
+				current.crawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+
+		case "sitemap":
+			if value != "" {
+				policy.sitemaps = append(policy.sitemaps, value)
+			}
+		}
+
+		lastWasAgent = false
+	}
+
+	var specific, wildcard []*robotsGroup
+	for _, group := range groups {
+		for _, name := range group.agents {
+			if name == "*" {
+				wildcard = append(wildcard, group)
+				break
+			}
+			if name != "" && robotsProductToken(name) == agent {
+				specific = append(specific, group)
+				break
+			}
+		}
+	}
+
+	selected := specific
+	if len(selected) == 0 {
+		selected = wildcard
+	}
+
+	for _, group := range selected {
+		policy.rules = append(policy.rules, group.rules...)
+		if group.crawlDelay > policy.crawlDelay {
+			policy.crawlDelay = group.crawlDelay
+		}
+	}
+
+	return policy
+}
+
+// robotsProductToken reduces a lowercased User-agent value to its product token ("feedaggregator/1.0" -> "feedaggregator")
+// Only an exact token match applies a group to us, so "User-agent: Feed" doesn't
+func robotsProductToken(name string) string {
+	if i := strings.IndexAny(name, "/ \t"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// newRobotsRule compiles a path pattern supporting * and a trailing $
+func newRobotsRule(allow bool, pattern string) robotsRule {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	if strings.HasSuffix(expr, `\$`) {
+		expr = strings.TrimSuffix(expr, `\$`) + "$"
+	}
+
+	return robotsRule{
+		allow:   allow,
+		pattern: pattern,
+		regex:   regexp.MustCompile("^" + expr),
+	}
+}
+
+// allows applies the longest matching rule; Allow wins ties
+func (policy *robotsPolicy) allows(target *url.URL) bool {
+	path := target.RequestURI()
+	if path == "/robots.txt" {
+		return true
+	}
+
+	allowed := true
+	matchedLength := -1
+
+	for _, rule := range policy.rules {
+		if !rule.regex.MatchString(path) {
+			continue
+		}
+
+		length := len(rule.pattern)
+		if length > matchedLength || (length == matchedLength && rule.allow) {
+			allowed = rule.allow
+			matchedLength = length
+		}
+	}
+
+	return allowed
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRobotsAgentGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		robots  string
+		path    string
+		allowed bool
+	}{
+		{
+			name:    "wildcard applies",
+			robots:  "User-agent: *\nDisallow: /private/",
+			path:    "/private/a",
+			allowed: false,
+		},
+		{
+			name:    "our group wins over wildcard",
+			robots:  "User-agent: *\nDisallow: /\n\nUser-agent: FeedAggregator\nDisallow: /drafts/",
+			path:    "/news/a",
+			allowed: true,
+		},
+		{
+			name:    "token matched case-insensitively",
+			robots:  "User-agent: feedaggregator\nDisallow: /news/",
+			path:    "/news/a",
+			allowed: false,
+		},
+		{
+			name:    "token with version matches",
+			robots:  "User-agent: FeedAggregator/1.0\nDisallow: /news/",
+			path:    "/news/a",
+			allowed: false,
+		},
+		{
+			name:    "prefix of our token is another crawler",
+			robots:  "User-agent: Feed\nDisallow: /\n\nUser-agent: *\nDisallow: /private/",
+			path:    "/news/a",
+			allowed: true,
+		},
+		{
+			name:    "single letter is another crawler",
+			robots:  "User-agent: M\nDisallow: /",
+			path:    "/news/a",
+			allowed: true,
+		},
+		{
+			name:    "longer token is another crawler",
+			robots:  "User-agent: FeedAggregatorBot\nDisallow: /",
+			path:    "/news/a",
+			allowed: true,
+		},
+		{
+			name:    "grouped agents share rules",
+			robots:  "User-agent: OtherBot\nUser-agent: FeedAggregator\nDisallow: /news/",
+			path:    "/news/a",
+			allowed: false,
+		},
+		{
+			name:    "longest rule wins",
+			robots:  "User-agent: *\nDisallow: /news/\nAllow: /news/public/",
+			path:    "/news/public/a",
+			allowed: true,
+		},
+		{
+			name:    "wildcard and end anchor",
+			robots:  "User-agent: *\nDisallow: /*.pdf$",
+			path:    "/files/report.pdf",
+			allowed: false,
+		},
+		{
+			name:    "empty disallow allows everything",
+			robots:  "User-agent: *\nDisallow:",
+			path:    "/anything",
+			allowed: true,
+		},
+		{
+			name:    "robots.txt always allowed",
+			robots:  "User-agent: *\nDisallow: /",
+			path:    "/robots.txt",
+			allowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		policy := parseRobots([]byte(tt.robots), robotsAgentToken)
+		target := &url.URL{Scheme: "https", Host: "example.com", Path: tt.path}
+		if got := policy.allows(target); got != tt.allowed {
+			t.Errorf("%s: allows(%s) = %v, want %v", tt.name, tt.path, got, tt.allowed)
+		}
+	}
+}
+
+func TestParseRobotsCrawlDelay(t *testing.T) {
+	policy := parseRobots([]byte("User-agent: Feed\nCrawl-delay: 30\n\nUser-agent: *\nCrawl-delay: 2.5\nSitemap: https://example.com/sitemap.xml"), robotsAgentToken)
+
+	if policy.crawlDelay != 2500*time.Millisecond {
+		t.Errorf("crawlDelay = %s, want 2.5s", policy.crawlDelay)
+	}
+	if len(policy.sitemaps) != 1 || policy.sitemaps[0] != "https://example.com/sitemap.xml" {
+		t.Errorf("sitemaps = %v", policy.sitemaps)
+	}
+}
+
+func TestRobotsForWaitsOnHostGate(t *testing.T) {
+	var fetches atomic.Int32
+	server := withTestHost(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		http.NotFound(w, r)
+	}))
+	target, _ := url.Parse(server.URL + "/news/")
+
+	politeness.backoff(target.Host, time.Now().Add(time.Hour))
+	t.Cleanup(func() {
+		politeness.mu.Lock()
+		delete(politeness.hosts, target.Host)
+		politeness.mu.Unlock()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := robotsFor(ctx, target)
+	if got := crawlErrorCode(err); got != CrawlErrorRateLimited {
+		t.Errorf("robotsFor on a backed-off host code = %q (%v), want %q", got, err, CrawlErrorRateLimited)
+	}
+	if n := fetches.Load(); n != 0 {
+		t.Errorf("robots.txt fetched %d times while the host was backed off", n)
+	}
+}
+
+func TestStoreRobotsBoundsCache(t *testing.T) {
+	robotsCacheMu.Lock()
+	saved := robotsCache
+	robotsCache = map[string]robotsCacheEntry{}
+	robotsCacheMu.Unlock()
+	t.Cleanup(func() {
+		robotsCacheMu.Lock()
+		robotsCache = saved
+		robotsCacheMu.Unlock()
+	})
+
+	robotsCacheMu.Lock()
+	defer robotsCacheMu.Unlock()
+
+	now := time.Now()
+	for i := 0; i < robotsCacheMaxHosts; i++ {
+		storeRobots(fmt.Sprintf("https://host%d.example", i), &robotsPolicy{}, now)
+	}
+
+	// Refreshing a cached host doesn't evict anything
+	storeRobots("https://host0.example", &robotsPolicy{}, now)
+	if len(robotsCache) != robotsCacheMaxHosts {
+		t.Fatalf("cache holds %d hosts after a refresh, want %d", len(robotsCache), robotsCacheMaxHosts)
+	}
+
+	// A new host makes room for itself
+	storeRobots("https://new.example", &robotsPolicy{}, now)
+	if len(robotsCache) != robotsCacheMaxHosts {
+		t.Errorf("cache holds %d hosts, want %d", len(robotsCache), robotsCacheMaxHosts)
+	}
+	if _, ok := robotsCache["https://new.example"]; !ok {
+		t.Error("new host was not cached")
+	}
+
+	// Once entries expire they are swept rather than evicted one at a time
+	storeRobots("https://later.example", &robotsPolicy{}, now.Add(robotsCacheTTL))
+	if len(robotsCache) != 1 {
+		t.Errorf("cache holds %d hosts after every entry expired, want 1", len(robotsCache))
+	}
+}
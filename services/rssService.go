@@ -0,0 +1,394 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go-lang-jwt/helpers"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// RSS 2.0 document
+type rssFeed struct {
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	Author      string  `xml:"author"`
+	Creator     string  `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	PubDate     string  `xml:"pubDate"`
+	DCDate      string  `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink string `xml:"isPermaLink,attr"`
+}
+
+// Atom 1.0 document
+type atomFeed struct {
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title   string     `xml:"title"`
+	ID      string     `xml:"id"`
+	Links   []atomLink `xml:"link"`
+	Summary string     `xml:"summary"`
+	Content string     `xml:"content"`
+	Authors []struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+// RSS 1.0 (RDF) document - items are siblings of the channel
+type rdfFeed struct {
+	Items []rssItem `xml:"item"`
+}
+
+// JSON Feed 1.x document
+type jsonFeed struct {
+	Version string         `json:"version"`
+	Items   []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	ExternalURL   string           `json:"external_url"`
+	Title         string           `json:"title"`
+	Summary       string           `json:"summary"`
+	ContentHTML   string           `json:"content_html"`
+	ContentText   string           `json:"content_text"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Author        *jsonFeedAuthor  `json:"author"`
+	Authors       []jsonFeedAuthor `json:"authors"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+// extractFromFeed fetches a feed URL and converts its items to articles
+// The boolean result reports a 304 Not Modified answer
+func extractFromFeed(ctx context.Context, feedURL string, validators *validatorSet) ([]ArticleData, bool, error) {
+	result, err := validators.fetch(ctx, feedURL, acceptFeed)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if result.NotModified {
+		return nil, true, nil
+	}
+
+	articles, err := parseFeed(result.Body)
+	return resolveFeedLinks(articles, result.URL), false, err
+}
+
+// resolveFeedLinks makes item links absolute against the feed's final URL,
+// dropping items whose link is not http(s)
+func resolveFeedLinks(articles []ArticleData, feedURL string) []ArticleData {
+	base, err := url.Parse(feedURL)
+	if err != nil {
+		return articles
+	}
+
+	resolved := articles[:0]
+	for _, article := range articles {
+		if article.URL = resolveLink(base, article.URL); article.URL != "" {
+			resolved = append(resolved, article)
+		}
+	}
+	return resolved
+}
+
+// parseFeed detects the feed format from the root element and parses it
+func parseFeed(body []byte) ([]ArticleData, error) {
+	if isJSONFeed(body) {
+		var feed jsonFeed
+		if err := json.Unmarshal(body, &feed); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON Feed: %v", err)
+		}
+		return jsonFeedItemsToArticles(feed.Items), nil
+	}
+
+	root, err := feedRootElement(body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(root) {
+	case "rss":
+		var feed rssFeed
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, fmt.Errorf("failed to parse RSS: %v", err)
+		}
+		return rssItemsToArticles(feed.Channel.Items), nil
+
+	case "feed":
+		var feed atomFeed
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, fmt.Errorf("failed to parse Atom: %v", err)
+		}
+		return atomEntriesToArticles(feed.Entries), nil
+
+	case "rdf":
+		var feed rdfFeed
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, fmt.Errorf("failed to parse RDF: %v", err)
+		}
+		return rssItemsToArticles(feed.Items), nil
+	}
+
+	return nil, fmt.Errorf("unsupported feed format: <%s>", root)
+}
+
+// feedRootElement returns the local name of the first XML element
+func feedRootElement(body []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", errors.New("not a valid XML feed")
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+// looksLikeFeed reports whether a response body is an XML feed rather than HTML
+func looksLikeFeed(result *FetchResult) bool {
+	if isJSONFeed(result.Body) {
+		return true
+	}
+
+	contentType := strings.ToLower(result.Header.Get("Content-Type"))
+	if strings.Contains(contentType, "rss") || strings.Contains(contentType, "atom") {
+		return true
+	}
+
+	if !strings.Contains(contentType, "xml") && !bytes.HasPrefix(bytes.TrimSpace(result.Body), []byte("<?xml")) {
+		return false
+	}
+
+	root, err := feedRootElement(result.Body)
+	if err != nil {
+		return false
+	}
+	root = strings.ToLower(root)
+	return root == "rss" || root == "feed" || root == "rdf"
+}
+
+// isJSONFeed reports whether a body is a JSON Feed document
+func isJSONFeed(body []byte) bool {
+	trimmed := bytes.TrimSpace(body)
+	if !bytes.HasPrefix(trimmed, []byte("{")) {
+		return false
+	}
+
+	var probe struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(trimmed, &probe); err != nil {
+		return false
+	}
+	return strings.Contains(probe.Version, "jsonfeed.org")
+}
+
+func rssItemsToArticles(items []rssItem) []ArticleData {
+	var articles []ArticleData
+
+	for _, item := range items {
+		title := strings.TrimSpace(item.Title)
+		guid := strings.TrimSpace(item.GUID.Value)
+		link := strings.TrimSpace(item.Link)
+
+		// A permalink GUID doubles as the article URL
+		if link == "" && item.GUID.IsPermaLink != "false" && isHTTPURL(guid) {
+			link = guid
+		}
+
+		if title == "" || link == "" {
+			continue
+		}
+
+		author := strings.TrimSpace(item.Author)
+		if author == "" {
+			author = strings.TrimSpace(item.Creator)
+		}
+
+		published := helpers.ParseDate(item.PubDate)
+		if published == nil {
+			published = helpers.ParseDate(item.DCDate)
+		}
+
+		summary := feedSummary(item.Description)
+
+		articles = append(articles, ArticleData{
+			Title:       title,
+			URL:         link,
+			GUID:        guid,
+			Summary:     summary,
+			PublishedAt: published,
+			Author:      author,
+		})
+	}
+
+	return articles
+}
+
+func atomEntriesToArticles(entries []atomEntry) []ArticleData {
+	var articles []ArticleData
+
+	for _, entry := range entries {
+		title := strings.TrimSpace(entry.Title)
+		link := atomEntryLink(entry.Links)
+
+		if link == "" && isHTTPURL(entry.ID) {
+			link = strings.TrimSpace(entry.ID)
+		}
+
+		if title == "" || link == "" {
+			continue
+		}
+
+		var author string
+		if len(entry.Authors) > 0 {
+			author = strings.TrimSpace(entry.Authors[0].Name)
+		}
+
+		published := helpers.ParseDate(entry.Published)
+		if published == nil {
+			published = helpers.ParseDate(entry.Updated)
+		}
+
+		summary := feedSummary(entry.Summary)
+		if summary == "" {
+			summary = feedSummary(entry.Content)
+		}
+
+		articles = append(articles, ArticleData{
+			Title:       title,
+			URL:         link,
+			GUID:        strings.TrimSpace(entry.ID),
+			Summary:     summary,
+			PublishedAt: published,
+			Author:      author,
+		})
+	}
+
+	return articles
+}
+
+func jsonFeedItemsToArticles(items []jsonFeedItem) []ArticleData {
+	var articles []ArticleData
+
+	for _, item := range items {
+		title := strings.TrimSpace(item.Title)
+		link := strings.TrimSpace(item.URL)
+		if link == "" {
+			link = strings.TrimSpace(item.ExternalURL)
+		}
+
+		if title == "" || link == "" {
+			continue
+		}
+
+		var author string
+		if len(item.Authors) > 0 {
+			author = strings.TrimSpace(item.Authors[0].Name)
+		} else if item.Author != nil {
+			author = strings.TrimSpace(item.Author.Name)
+		}
+
+		published := helpers.ParseDate(item.DatePublished)
+		if published == nil {
+			published = helpers.ParseDate(item.DateModified)
+		}
+
+		summary := truncateSummary(strings.TrimSpace(item.Summary))
+		if summary == "" {
+			summary = feedSummary(item.ContentHTML)
+		}
+		if summary == "" {
+			summary = feedSummary(item.ContentText)
+		}
+
+		articles = append(articles, ArticleData{
+			Title:       title,
+			URL:         link,
+			GUID:        strings.TrimSpace(item.ID),
+			Summary:     summary,
+			PublishedAt: published,
+			Author:      author,
+		})
+	}
+
+	return articles
+}
+
+// atomEntryLink picks the alternate link of an Atom entry
+func atomEntryLink(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return strings.TrimSpace(link.Href)
+		}
+	}
+	return ""
+}
+
+// feedSummary converts an HTML description to plain text capped at 500 characters
+func feedSummary(description string) string {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return ""
+	}
+
+	text := description
+	if doc, err := goquery.NewDocumentFromReader(strings.NewReader(description)); err == nil {
+		text = doc.Text()
+	}
+
+	return truncateSummary(strings.Join(strings.Fields(text), " "))
+}
+
+// truncateSummary caps summaries at 500 characters (runes, so multi-byte text stays valid UTF-8)
+func truncateSummary(summary string) string {
+	count := 0
+	for i := range summary {
+		if count == 500 {
+			return summary[:i] + "..."
+		}
+		count++
+	}
+	return summary
+}
+
+func isHTTPURL(value string) bool {
+	value = strings.TrimSpace(value)
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// feedItem is the part of a parsed article the feed tests compare
+type feedItem struct {
+	title, url, author, published, summary string
+}
+
+func TestParseFeedFormats(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []feedItem
+	}{
+		{
+			name: "rss",
+			body: `<?xml version="1.0"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel>
+	<item>
+		<title> First </title>
+		<link>https://news.example/first</link>
+		<description>&lt;p&gt;Hello &lt;b&gt;world&lt;/b&gt;&lt;/p&gt;</description>
+		<author>alice@news.example</author>
+		<pubDate>Mon, 02 Jan 2006 15:04:05 +0000</pubDate>
+	</item>
+	<item>
+		<title>Permalink GUID</title>
+		<guid>https://news.example/guid</guid>
+		<dc:creator>Bob</dc:creator>
+		<dc:date>2006-01-03T10:00:00Z</dc:date>
+	</item>
+	<item>
+		<title>Opaque GUID</title>
+		<guid isPermaLink="false">https://news.example/opaque</guid>
+	</item>
+	<item>
+		<title>Relative link</title>
+		<link>/relative</link>
+	</item>
+	<item><link>https://news.example/untitled</link></item>
+</channel></rss>`,
+			want: []feedItem{
+				{"First", "https://news.example/first", "alice@news.example", "2006-01-02T15:04:05Z", "Hello world"},
+				{"Permalink GUID", "https://news.example/guid", "Bob", "2006-01-03T10:00:00Z", ""},
+				{"Relative link", "https://news.example/relative", "", "", ""},
+			},
+		},
+		{
+			name: "atom",
+			body: `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<entry>
+		<title>Alternate link</title>
+		<id>tag:news.example,2006:1</id>
+		<link rel="self" href="https://news.example/self"/>
+		<link rel="alternate" href="https://news.example/alternate"/>
+		<author><name>Carol</name></author>
+		<published>2006-01-02T15:04:05+02:00</published>
+		<updated>2006-01-05T00:00:00Z</updated>
+		<summary>Short summary</summary>
+	</entry>
+	<entry>
+		<title>Link from ID</title>
+		<id>https://news.example/from-id</id>
+		<updated>2006-01-04T08:00:00Z</updated>
+		<content type="html">&lt;p&gt;Body text&lt;/p&gt;</content>
+	</entry>
+	<entry>
+		<title>Relative</title>
+		<id>urn:uuid:1</id>
+		<link href="articles/relative"/>
+	</entry>
+	<entry>
+		<title>No link</title>
+		<id>urn:uuid:2</id>
+	</entry>
+</feed>`,
+			want: []feedItem{
+				{"Alternate link", "https://news.example/alternate", "Carol", "2006-01-02T13:04:05Z", "Short summary"},
+				{"Link from ID", "https://news.example/from-id", "", "2006-01-04T08:00:00Z", "Body text"},
+				{"Relative", "https://news.example/feeds/articles/relative", "", "", ""},
+			},
+		},
+		{
+			name: "rdf",
+			body: `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+	<channel><title>Example</title></channel>
+	<item>
+		<title>RDF item</title>
+		<link>https://news.example/rdf</link>
+		<description>Plain description</description>
+		<dc:creator>Dave</dc:creator>
+		<dc:date>2006-01-02</dc:date>
+	</item>
+</rdf:RDF>`,
+			want: []feedItem{
+				{"RDF item", "https://news.example/rdf", "Dave", "2006-01-02T00:00:00Z", "Plain description"},
+			},
+		},
+		{
+			name: "json feed",
+			body: `{
+	"version": "https://jsonfeed.org/version/1.1",
+	"items": [
+		{
+			"id": "1",
+			"url": "https://news.example/json",
+			"title": "JSON item",
+			"summary": "Given summary",
+			"content_html": "<p>Ignored</p>",
+			"authors": [{"name": "Erin"}],
+			"author": {"name": "Ignored"},
+			"date_published": "2006-01-02T15:04:05Z"
+		},
+		{
+			"id": "2",
+			"external_url": "/external",
+			"title": "External",
+			"content_text": "Text only",
+			"author": {"name": "Frank"},
+			"date_modified": "2006-01-06T00:00:00Z"
+		},
+		{"id": "3", "title": "No URL"}
+	]
+}`,
+			want: []feedItem{
+				{"JSON item", "https://news.example/json", "Erin", "2006-01-02T15:04:05Z", "Given summary"},
+				{"External", "https://news.example/external", "Frank", "2006-01-06T00:00:00Z", "Text only"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles, err := parseFeed([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("parseFeed: %v", err)
+			}
+			articles = resolveFeedLinks(articles, "https://news.example/feeds/main.xml")
+
+			if len(articles) != len(tt.want) {
+				t.Fatalf("got %d articles, want %d: %+v", len(articles), len(tt.want), articles)
+			}
+			for i, article := range articles {
+				got := feedItem{article.Title, article.URL, article.Author, "", article.Summary}
+				if article.PublishedAt != nil {
+					got.published = article.PublishedAt.Format(time.RFC3339)
+				}
+				if got != tt.want[i] {
+					t.Errorf("article %d = %+v, want %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseFeedRejectsOtherDocuments(t *testing.T) {
+	for _, body := range []string{
+		`<html><body>Not a feed</body></html>`,
+		`{"version": "1.0", "items": []}`,
+		`not xml at all`,
+	} {
+		if articles, err := parseFeed([]byte(body)); err == nil {
+			t.Errorf("parseFeed(%q) = %v, want error", body, articles)
+		}
+	}
+}
+
+func TestTruncateSummary(t *testing.T) {
+	short := strings.Repeat("é", 500)
+	if got := truncateSummary(short); got != short {
+		t.Errorf("500 characters were truncated to %d", utf8.RuneCountInString(got))
+	}
+
+	got := truncateSummary(strings.Repeat("é", 501))
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncated summary is not valid UTF-8: %q", got)
+	}
+	if want := strings.Repeat("é", 500) + "..."; got != want {
+		t.Errorf("got %d characters, want 500 plus an ellipsis", utf8.RuneCountInString(got))
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-lang-jwt/database"
+	"go-lang-jwt/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SavedArticleView is a starred or saved article with when it was marked
+type SavedArticleView struct {
+	Kind    models.SavedArticleKind `json:"kind"`
+	SavedAt time.Time               `json:"saved_at"`
+	Article models.Article          `json:"article"`
+}
+
+// MarkArticle stars or saves an article the user can access; marking twice is a no-op
+func MarkArticle(ctx context.Context, userID string, isAdmin bool, articleID string, kind models.SavedArticleKind) error {
+	article, err := GetArticleForUser(ctx, userID, isAdmin, articleID)
+	if err != nil {
+		return err
+	}
+
+	savedCollection := database.OpenCollection(database.Client, "saved_articles")
+
+	filter := bson.M{"user_id": userID, "article_id": article.ID, "kind": kind}
+	_, err = savedCollection.UpdateOne(ctx, filter, bson.M{
+		"$setOnInsert": bson.M{
+			"_id":        primitive.NewObjectID(),
+			"created_at": time.Now(),
+		},
+	}, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save article: %v", err)
+	}
+
+	return nil
+}
+
+// UnmarkArticle removes a star or save; removing a missing one is a no-op
+func UnmarkArticle(ctx context.Context, userID string, articleID string, kind models.SavedArticleKind) error {
+	objectID, err := primitive.ObjectIDFromHex(articleID)
+	if err != nil {
+		return errors.New("invalid article ID format")
+	}
+
+	savedCollection := database.OpenCollection(database.Client, "saved_articles")
+
+	_, err = savedCollection.DeleteOne(ctx, bson.M{"user_id": userID, "article_id": objectID, "kind": kind})
+	if err != nil {
+		return fmt.Errorf("failed to unsave article: %v", err)
+	}
+
+	return nil
+}
+
+// ListSavedArticles returns the user's starred and/or saved articles, most recently marked first
+// An empty kind lists both
+func ListSavedArticles(ctx context.Context, userID string, kind models.SavedArticleKind, page int, limit int) ([]SavedArticleView, int64, error) {
+	savedCollection := database.OpenCollection(database.Client, "saved_articles")
+	articleCollection := database.OpenCollection(database.Client, "articles")
+
+	filter := bson.M{"user_id": userID}
+	if kind != "" {
+		filter["kind"] = kind
+	}
+
+	totalCount, err := savedCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count saved articles: %v", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := savedCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch saved articles: %v", err)
+	}
+
+	var marks []models.SavedArticle
+	if err := cursor.All(ctx, &marks); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode saved articles: %v", err)
+	}
+
+	// Load the articles themselves (without content, like the feed)
+	articleIDs := make([]primitive.ObjectID, 0, len(marks))
+	for _, mark := range marks {
+		articleIDs = append(articleIDs, mark.Article_id)
+	}
+
+	articleCursor, err := articleCollection.Find(ctx, bson.M{"_id": bson.M{"$in": articleIDs}},
+		options.Find().SetProjection(bson.M{"content_html": 0, "content_text": 0}))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch articles: %v", err)
+	}
+
+	var articles []models.Article
+	if err := articleCursor.All(ctx, &articles); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode articles: %v", err)
+	}
+
+	articleMap := make(map[primitive.ObjectID]models.Article)
+	for _, article := range articles {
+		articleMap[article.ID] = article
+	}
+
+	saved := []SavedArticleView{}
+	for _, mark := range marks {
+		article, ok := articleMap[mark.Article_id]
+		if !ok {
+			continue // deleted by a retention policy that doesn't keep saved articles
+		}
+		saved = append(saved, SavedArticleView{
+			Kind:    mark.Kind,
+			SavedAt: mark.Created_at,
+			Article: article,
+		})
+	}
+
+	return saved, totalCount, nil
+}
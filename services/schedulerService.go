@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"time"
+
+	"go-lang-jwt/database"
+	"go-lang-jwt/helpers"
+	"go-lang-jwt/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Scheduler settings
+var (
+	schedulerEnabled     = helpers.GetEnvInt("CRAWL_SCHEDULER_ENABLED", 1) == 1
+	schedulerTick        = helpers.GetEnvDuration("CRAWL_SCHEDULER_TICK", time.Minute)
+	schedulerBatchSize   = helpers.GetEnvInt("CRAWL_SCHEDULER_BATCH", 50)
+	defaultCrawlInterval = helpers.GetEnvDuration("CRAWL_INTERVAL_DEFAULT", time.Hour)
+	minCrawlInterval     = helpers.GetEnvDuration("CRAWL_INTERVAL_MIN", 15*time.Minute)
+	maxCrawlInterval     = helpers.GetEnvDuration("CRAWL_INTERVAL_MAX", 24*time.Hour)
+)
+
+// Retry settings for transient failures
+var (
+	retryBaseDelay   = helpers.GetEnvDuration("CRAWL_RETRY_BASE", time.Minute)
+	retryMaxDelay    = helpers.GetEnvDuration("CRAWL_RETRY_MAX", 6*time.Hour)
+	unreachableAfter = helpers.GetEnvInt("CRAWL_UNREACHABLE_AFTER", 5)
+)
+
+// schedulerUserID owns the jobs the scheduler enqueues
+const schedulerUserID = "scheduler"
+
+// StartCrawlScheduler periodically enqueues crawl jobs for sources that are due
+func StartCrawlScheduler(ctx context.Context) {
+	if !schedulerEnabled {
+		log.Println("Crawl scheduler disabled")
+		return
+	}
+
+	log.Printf("Starting crawl scheduler (every %s)", schedulerTick)
+
+	go func() {
+		ticker := time.NewTicker(schedulerTick)
+		defer ticker.Stop()
+
+		for {
+			if err := scheduleDueSources(ctx); err != nil {
+				log.Printf("Crawl scheduler: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// scheduleDueSources enqueues a job for every subscribed source whose next crawl is due
+func scheduleDueSources(ctx context.Context) error {
+	tickCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	sourceCollection := database.OpenCollection(database.Client, "sources")
+	subscriptionCollection := database.OpenCollection(database.Client, "subscriptions")
+
+	// Step 1: Only sources somebody subscribes to are crawled
+	subscribed, err := subscriptionCollection.Distinct(tickCtx, "source_id", bson.M{})
+	if err != nil {
+		return err
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	// Step 2: Find due sources (sources created before scheduling have no next_crawl_at)
+	now := time.Now()
+	filter := bson.M{
+		"_id": bson.M{"$in": subscribed},
+		"$or": []bson.M{
+			{"next_crawl_at": bson.M{"$lte": now}},
+			{"next_crawl_at": nil},
+		},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "next_crawl_at", Value: 1}}).
+		SetLimit(int64(schedulerBatchSize))
+
+	cursor, err := sourceCollection.Find(tickCtx, filter, opts)
+	if err != nil {
+		return err
+	}
+
+	var sources []models.Source
+	if err := cursor.All(tickCtx, &sources); err != nil {
+		return err
+	}
+
+	// Step 3: Claim and enqueue each source
+	for _, source := range sources {
+		claimed, err := claimDueSource(tickCtx, source, now)
+		if err != nil {
+			log.Printf("Crawl scheduler: failed to claim %s: %v", source.URL, err)
+			continue
+		}
+		if !claimed {
+			continue // another replica got it first
+		}
+
+		pending, err := hasPendingCrawlJob(tickCtx, source.ID)
+		if err != nil || pending {
+			continue
+		}
+
+		if _, err := EnqueueCrawlJob(tickCtx, schedulerUserID, source.ID); err != nil {
+			log.Printf("Crawl scheduler: failed to enqueue %s: %v", source.URL, err)
+		}
+	}
+
+	return nil
+}
+
+// claimDueSource pushes next_crawl_at forward so no other replica schedules the source again
+// The crawl itself sets the real next_crawl_at when it finishes
+func claimDueSource(ctx context.Context, source models.Source, now time.Time) (bool, error) {
+	sourceCollection := database.OpenCollection(database.Client, "sources")
+
+	filter := bson.M{"_id": source.ID}
+	if source.NextCrawlAt != nil {
+		filter["next_crawl_at"] = source.NextCrawlAt
+	} else {
+		filter["next_crawl_at"] = nil
+	}
+
+	result, err := sourceCollection.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{"next_crawl_at": jitter(now.Add(crawlIntervalOf(source)))},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// hasPendingCrawlJob reports whether the source already has a queued or running job
+func hasPendingCrawlJob(ctx context.Context, sourceID primitive.ObjectID) (bool, error) {
+	jobCollection := database.OpenCollection(database.Client, "crawl_jobs")
+
+	err := jobCollection.FindOne(ctx, bson.M{
+		"source_id": sourceID,
+		"status":    bson.M{"$in": []models.CrawlJobStatus{models.CrawlJobQueued, models.CrawlJobRunning}},
+	}).Err()
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// crawlIntervalOf returns the source's current interval, or the default for new sources
+func crawlIntervalOf(source models.Source) time.Duration {
+	if source.CrawlIntervalMinutes <= 0 {
+		return defaultCrawlInterval
+	}
+	return time.Duration(source.CrawlIntervalMinutes) * time.Minute
+}
+
+// nextCrawlInterval adapts the interval to the last crawl:
+// new articles halve it, no changes grow it by half, failures double it
+func nextCrawlInterval(current time.Duration, savedCount int, failed bool) time.Duration {
+	next := current
+	switch {
+	case failed:
+		next = current * 2
+	case savedCount > 0:
+		next = current / 2
+	default:
+		next = current * 3 / 2
+	}
+
+	if next < minCrawlInterval {
+		next = minCrawlInterval
+	}
+	if next > maxCrawlInterval {
+		next = maxCrawlInterval
+	}
+	return next
+}
+
+// retryDelay is the exponential backoff after the given number of consecutive failures,
+// with "equal jitter": half the delay is fixed, the other half random
+func retryDelay(failures int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < failures && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// jitter spreads scheduled crawls by up to ±10% of the delay so sources don't align
+func jitter(at time.Time) time.Time {
+	delay := time.Until(at)
+	if delay <= 0 {
+		return at
+	}
+
+	spread := int64(delay / 5)
+	if spread <= 0 {
+		return at
+	}
+	return at.Add(time.Duration(rand.Int63n(spread)) - time.Duration(spread/2))
+}
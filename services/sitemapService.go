@@ -0,0 +1,256 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"go-lang-jwt/helpers"
+)
+
+// maxChildSitemaps caps how many child sitemaps of an index are fetched per crawl
+const maxChildSitemaps = 5
+
+// maxSitemapDepth caps nested sitemap index recursion
+const maxSitemapDepth = 2
+
+// Sitemap index document
+type sitemapIndex struct {
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// Sitemap URL set, including Google News extensions
+type sitemapURLSet struct {
+	URLs []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string       `xml:"loc"`
+	LastMod string       `xml:"lastmod"`
+	News    *sitemapNews `xml:"news"`
+}
+
+type sitemapNews struct {
+	Title           string `xml:"title"`
+	PublicationDate string `xml:"publication_date"`
+}
+
+// sitemapReader walks a sitemap tree for one crawl
+type sitemapReader struct {
+	since      *time.Time
+	validators *validatorSet
+}
+
+// extractFromSitemap reads a sitemap (or index) and returns entries newer than since
+// The second return value is the number of entries seen before date filtering;
+// unchanged sitemaps (a 304, or an index child with an old lastmod) count as one so they are not mistaken for empty ones
+func extractFromSitemap(ctx context.Context, sitemapURL string, since *time.Time, validators *validatorSet) ([]ArticleData, int, error) {
+	reader := &sitemapReader{since: since, validators: validators}
+	return reader.read(ctx, sitemapURL, 0)
+}
+
+func (reader *sitemapReader) read(ctx context.Context, sitemapURL string, depth int) ([]ArticleData, int, error) {
+	result, err := reader.validators.fetch(ctx, sitemapURL, acceptSitemap)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if result.NotModified {
+		return nil, 1, nil
+	}
+
+	body, err := decompressSitemap(result.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	root, err := feedRootElement(body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid sitemap: %v", err)
+	}
+
+	switch strings.ToLower(root) {
+	case "sitemapindex":
+		if depth >= maxSitemapDepth {
+			return nil, 0, fmt.Errorf("sitemap index nested too deeply: %s", sitemapURL)
+		}
+
+		var index sitemapIndex
+		if err := xml.Unmarshal(body, &index); err != nil {
+			return nil, 0, fmt.Errorf("failed to parse sitemap index: %v", err)
+		}
+		return reader.readIndex(ctx, index, depth)
+
+	case "urlset":
+		var urlSet sitemapURLSet
+		if err := xml.Unmarshal(body, &urlSet); err != nil {
+			return nil, 0, fmt.Errorf("failed to parse sitemap: %v", err)
+		}
+		return sitemapURLsToArticles(urlSet.URLs, reader.since), len(urlSet.URLs), nil
+	}
+
+	return nil, 0, fmt.Errorf("unsupported sitemap format: <%s>", root)
+}
+
+// readSitemapIndex follows the most recently modified child sitemaps
+func (reader *sitemapReader) readIndex(ctx context.Context, index sitemapIndex, depth int) ([]ArticleData, int, error) {
+	children := index.Sitemaps
+
+	// Newest child sitemaps first; undated ones last
+	sort.SliceStable(children, func(i, j int) bool {
+		return dateAfter(helpers.ParseDate(children[i].LastMod), helpers.ParseDate(children[j].LastMod))
+	})
+
+	var articles []ArticleData
+	seen := 0
+	fetched := 0
+
+	for _, child := range children {
+		if fetched >= maxChildSitemaps {
+			break
+		}
+
+		// Skip child sitemaps that have not changed since the last crawl;
+		// like a 304 they count as seen, so an unchanged index means "nothing new"
+		lastMod := helpers.ParseDate(child.LastMod)
+		if reader.since != nil && lastMod != nil && !lastMod.After(*reader.since) {
+			seen++
+			continue
+		}
+
+		loc := strings.TrimSpace(child.Loc)
+		if loc == "" {
+			continue
+		}
+
+		fetched++
+		childArticles, childSeen, err := reader.read(ctx, loc, depth+1)
+		if err != nil {
+			log.Printf("Failed to read child sitemap %s: %v", loc, err)
+			continue
+		}
+
+		articles = append(articles, childArticles...)
+		seen += childSeen
+	}
+
+	return articles, seen, nil
+}
+
+// decompressSitemap transparently gunzips .xml.gz sitemaps
+func decompressSitemap(body []byte) ([]byte, error) {
+	if len(body) < 2 || body[0] != 0x1f || body[1] != 0x8b {
+		return body, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gzipped sitemap: %v", err)
+	}
+	defer reader.Close()
+
+	// Bound the decompressed size too, so a small archive can't expand without limit
+	decompressed, err := readLimitedBody(reader, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gzipped sitemap: %w", err)
+	}
+	return decodeToUTF8(decompressed, ""), nil
+}
+
+func sitemapURLsToArticles(urls []sitemapURL, since *time.Time) []ArticleData {
+	var articles []ArticleData
+
+	for _, entry := range urls {
+		loc := strings.TrimSpace(entry.Loc)
+		if loc == "" {
+			continue
+		}
+
+		// Google News publication date wins over lastmod
+		var published *time.Time
+		var title string
+		if entry.News != nil {
+			published = helpers.ParseDate(entry.News.PublicationDate)
+			title = strings.TrimSpace(entry.News.Title)
+		}
+		if published == nil {
+			published = helpers.ParseDate(entry.LastMod)
+		}
+
+		// Only entries changed since the last crawl
+		if since != nil && published != nil && !published.After(*since) {
+			continue
+		}
+
+		slugTitle := title == ""
+		if slugTitle {
+			title = titleFromURL(loc)
+		}
+		if title == "" || len(title) < 10 {
+			continue
+		}
+
+		articles = append(articles, ArticleData{
+			Title:        title,
+			URL:          loc,
+			PublishedAt:  published,
+			titleFromURL: slugTitle,
+		})
+	}
+
+	// Newest first, so the crawl enriches and stores the freshest entries before older ones
+	sort.SliceStable(articles, func(i, j int) bool {
+		return dateAfter(articles[i].PublishedAt, articles[j].PublishedAt)
+	})
+
+	return articles
+}
+
+// titleFromURL derives a readable title from the last path segment of a URL
+// e.g. /2024/05/big-news-today.html -> "Big news today"
+func titleFromURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	slug := path.Base(strings.TrimSuffix(parsed.Path, "/"))
+	slug = strings.TrimSuffix(slug, path.Ext(slug))
+
+	words := strings.FieldsFunc(slug, func(r rune) bool {
+		return r == '-' || r == '_' || r == '+'
+	})
+	if len(words) < 2 {
+		return ""
+	}
+
+	title := strings.Join(words, " ")
+	runes := []rune(title)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// dateAfter orders dated values before nil ones, newest first
+func dateAfter(a *time.Time, b *time.Time) bool {
+	if a == nil {
+		return false
+	}
+	if b == nil {
+		return true
+	}
+	return a.After(*b)
+}
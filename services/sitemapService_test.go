@@ -0,0 +1,205 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go-lang-jwt/models"
+)
+
+// withTestHost serves handler on a loopback server the crawler is allowed to fetch without waiting between requests
+func withTestHost(t *testing.T, handler http.Handler) *httptest.Server {
+	withAllowlist(t, "127.0.0.1")
+
+	savedDelay := minHostDelay
+	minHostDelay = 0
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		server.Close()
+		minHostDelay = savedDelay
+
+		robotsCacheMu.Lock()
+		delete(robotsCache, server.URL)
+		robotsCacheMu.Unlock()
+	})
+	return server
+}
+
+func gzipped(t *testing.T, body string) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSitemapURLsToArticles(t *testing.T) {
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	urls := []sitemapURL{
+		{Loc: "https://news.example/2026/10/older-but-still-new.html", LastMod: "2026-10-02"},
+		{Loc: "https://news.example/2026/09/already-crawled-story", LastMod: "2026-09-30T23:00:00Z"},
+		{Loc: "https://news.example/2026/10/undated-story-slug"},
+		{
+			Loc:     "https://news.example/a/12345",
+			LastMod: "2020-01-01",
+			News:    &sitemapNews{Title: "Newsroom title wins over slug", PublicationDate: "2026-10-05T08:00:00+02:00"},
+		},
+		{Loc: "https://news.example/2026/10/tiny-slug", LastMod: "2026-10-03"},
+		{Loc: "https://news.example/12345", LastMod: "2026-10-03"},
+		{Loc: "  "},
+	}
+
+	articles := sitemapURLsToArticles(urls, &since)
+
+	want := []struct {
+		title        string
+		published    string
+		titleFromURL bool
+	}{
+		{"Newsroom title wins over slug", "2026-10-05T06:00:00Z", false},
+		{"Older but still new", "2026-10-02T00:00:00Z", true},
+		{"Undated story slug", "", true},
+	}
+
+	if len(articles) != len(want) {
+		t.Fatalf("got %d articles, want %d: %+v", len(articles), len(want), articles)
+	}
+	for i, article := range articles {
+		published := ""
+		if article.PublishedAt != nil {
+			published = article.PublishedAt.Format(time.RFC3339)
+		}
+		if article.Title != want[i].title || published != want[i].published || article.titleFromURL != want[i].titleFromURL {
+			t.Errorf("article %d = %q published %q (slug title %v), want %q published %q (slug title %v)",
+				i, article.Title, published, article.titleFromURL, want[i].title, want[i].published, want[i].titleFromURL)
+		}
+	}
+}
+
+func TestDecompressSitemap(t *testing.T) {
+	plain := `<?xml version="1.0"?><urlset></urlset>`
+
+	if got, err := decompressSitemap([]byte(plain)); err != nil || string(got) != plain {
+		t.Errorf("plain sitemap = %q, %v; want it unchanged", got, err)
+	}
+
+	if got, err := decompressSitemap(gzipped(t, plain)); err != nil || string(got) != plain {
+		t.Errorf("gzipped sitemap = %q, %v; want %q", got, err, plain)
+	}
+
+	if _, err := decompressSitemap([]byte{0x1f, 0x8b, 0x00}); err == nil {
+		t.Error("truncated gzip stream decompressed without error")
+	}
+}
+
+func TestExtractFromSitemapIndex(t *testing.T) {
+	var mu sync.Mutex
+	requested := map[string]bool{}
+
+	urlset := func(entries string) string {
+		return `<?xml version="1.0"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:news="http://www.google.com/schemas/sitemap-news/0.9">` + entries + `</urlset>`
+	}
+	index := func(entries string) string {
+		return `<?xml version="1.0"?><sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` + entries + `</sitemapindex>`
+	}
+
+	var server *httptest.Server
+	server = withTestHost(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		mu.Unlock()
+
+		base := server.URL
+		switch r.URL.Path {
+		case "/sitemap_index.xml":
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(index(`
+				<sitemap><loc>` + base + `/old.xml</loc><lastmod>2020-01-01</lastmod></sitemap>
+				<sitemap><loc>` + base + `/news.xml</loc><lastmod>2026-10-10</lastmod></sitemap>
+				<sitemap><loc>` + base + `/archive.xml.gz</loc><lastmod>2026-10-09</lastmod></sitemap>
+				<sitemap><loc>` + base + `/nested.xml</loc><lastmod>2026-10-12</lastmod></sitemap>`)))
+		case "/nested.xml":
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(index(`
+				<sitemap><loc>` + base + `/deep.xml</loc></sitemap>
+				<sitemap><loc>` + base + `/too-deep.xml</loc></sitemap>`)))
+		case "/too-deep.xml":
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(index(`<sitemap><loc>` + base + `/never.xml</loc></sitemap>`)))
+		case "/deep.xml":
+			w.Header().Set("Content-Type", "text/xml")
+			w.Write([]byte(urlset(`<url><loc>` + base + `/2026/10/deep-nested-article-title</loc><lastmod>2026-10-11</lastmod></url>`)))
+		case "/news.xml":
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(urlset(`
+				<url>
+					<loc>` + base + `/a/1</loc>
+					<lastmod>2020-01-01</lastmod>
+					<news:news>
+						<news:title>Big news from the newsroom</news:title>
+						<news:publication_date>2026-10-10T08:00:00Z</news:publication_date>
+					</news:news>
+				</url>
+				<url><loc>` + base + `/2026/09/stale-story-here</loc><lastmod>2026-09-01</lastmod></url>`)))
+		case "/archive.xml.gz":
+			w.Header().Set("Content-Type", "application/x-gzip")
+			w.Write(gzipped(t, urlset(`<url><loc>`+base+`/2026/10/compressed-entry-slug-title</loc><lastmod>2026-10-09</lastmod></url>`)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	articles, seen, err := extractFromSitemap(ctx, server.URL+"/sitemap_index.xml", &since, newValidatorSet(models.Source{}))
+	if err != nil {
+		t.Fatalf("extractFromSitemap: %v", err)
+	}
+
+	// Children are read newest first; the unchanged one counts as seen without a fetch
+	wantURLs := []string{
+		server.URL + "/2026/10/deep-nested-article-title",
+		server.URL + "/a/1",
+		server.URL + "/2026/10/compressed-entry-slug-title",
+	}
+	if len(articles) != len(wantURLs) {
+		t.Fatalf("got %d articles, want %d: %+v", len(articles), len(wantURLs), articles)
+	}
+	for i, article := range articles {
+		if article.URL != wantURLs[i] {
+			t.Errorf("article %d URL = %s, want %s", i, article.URL, wantURLs[i])
+		}
+	}
+	if articles[1].Title != "Big news from the newsroom" {
+		t.Errorf("news sitemap title = %q", articles[1].Title)
+	}
+	if articles[2].Title != "Compressed entry slug title" {
+		t.Errorf("gzipped sitemap title = %q", articles[2].Title)
+	}
+
+	if seen != 5 {
+		t.Errorf("seen = %d, want 5 (4 listed entries plus the unchanged child)", seen)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if requested["/old.xml"] {
+		t.Error("child sitemap unchanged since the last crawl was fetched")
+	}
+	if !requested["/too-deep.xml"] || requested["/never.xml"] {
+		t.Errorf("recursion past maxSitemapDepth: requested %v", requested)
+	}
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-lang-jwt/database"
+	"go-lang-jwt/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetSourceForUser returns a source the user is subscribed to
+// Admins can access any source
+func GetSourceForUser(ctx context.Context, userID string, isAdmin bool, sourceID string) (*models.Source, error) {
+	objectID, err := primitive.ObjectIDFromHex(sourceID)
+	if err != nil {
+		return nil, errors.New("invalid source ID format")
+	}
+
+	sourceCollection := database.OpenCollection(database.Client, "sources")
+	subscriptionCollection := database.OpenCollection(database.Client, "subscriptions")
+
+	if !isAdmin {
+		count, err := subscriptionCollection.CountDocuments(ctx, bson.M{
+			"user_id":   userID,
+			"source_id": objectID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query subscription: %v", err)
+		}
+		if count == 0 {
+			return nil, errors.New("source not found or unauthorized")
+		}
+	}
+
+	var source models.Source
+	err = sourceCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&source)
+	if err == mongo.ErrNoDocuments {
+		return nil, errors.New("source not found or unauthorized")
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to query source: %v", err)
+	}
+
+	return &source, nil
+}
+
+// SetExtractionRules attaches extraction rules to a source, or removes them when rules is nil
+// Cache validators are cleared and the source is rescheduled so the new rules apply on the next crawl
+func SetExtractionRules(ctx context.Context, sourceID string, rules *models.ExtractionRules) (*models.Source, error) {
+	objectID, err := primitive.ObjectIDFromHex(sourceID)
+	if err != nil {
+		return nil, errors.New("invalid source ID format")
+	}
+
+	if rules != nil {
+		if _, err := compileExtractionRules(rules); err != nil {
+			return nil, fmt.Errorf("invalid extraction rules: %v", err)
+		}
+	}
+
+	sourceCollection := database.OpenCollection(database.Client, "sources")
+
+	now := time.Now()
+	set := bson.M{
+		"validators":    []models.FetchValidator{},
+		"etag":          "",
+		"last_modified": "",
+		"next_crawl_at": now,
+		"updated_at":    now,
+	}
+	update := bson.M{"$set": set}
+	if rules != nil {
+		set["extraction_rules"] = rules
+	} else {
+		update["$unset"] = bson.M{"extraction_rules": ""}
+	}
+
+	var source models.Source
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = sourceCollection.FindOneAndUpdate(ctx, bson.M{"_id": objectID}, update, opts).Decode(&source)
+	if err == mongo.ErrNoDocuments {
+		return nil, errors.New("source not found")
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to update source: %v", err)
+	}
+
+	return &source, nil
+}
+
+// SourceSettings are admin-tunable crawl options; nil fields are left unchanged
+type SourceSettings struct {
+	FetchArticlePages *bool `json:"fetch_article_pages"`
+	ExtractContent    *bool `json:"extract_content"`
+
+	NearDuplicateDistance *int `json:"near_duplicate_distance"` // -1 turns near-duplicate flagging off
+
+	// Retention overrides; only the fields present are changed
+	Retention *models.RetentionPolicy `json:"retention"`
+}
+
+// UpdateSourceSettings applies the non-nil settings to a source
+func UpdateSourceSettings(ctx context.Context, sourceID string, settings SourceSettings) (*models.Source, error) {
+	objectID, err := primitive.ObjectIDFromHex(sourceID)
+	if err != nil {
+		return nil, errors.New("invalid source ID format")
+	}
+
+	if settings.NearDuplicateDistance != nil {
+		if err := validateNearDuplicateDistance(*settings.NearDuplicateDistance); err != nil {
+			return nil, err
+		}
+	}
+
+	if settings.Retention != nil {
+		if err := validateRetentionPolicy(settings.Retention); err != nil {
+			return nil, err
+		}
+	}
+
+	set := bson.M{"updated_at": time.Now()}
+	if settings.FetchArticlePages != nil {
+		set["fetch_article_pages"] = *settings.FetchArticlePages
+	}
+	if settings.ExtractContent != nil {
+		set["extract_content"] = *settings.ExtractContent
+	}
+	if settings.NearDuplicateDistance != nil {
+		set["near_duplicate_distance"] = *settings.NearDuplicateDistance
+	}
+	if retention := settings.Retention; retention != nil {
+		if retention.MaxArticles != nil {
+			set["retention.max_articles"] = *retention.MaxArticles
+		}
+		if retention.MaxAgeDays != nil {
+			set["retention.max_age_days"] = *retention.MaxAgeDays
+		}
+		if retention.KeepSaved != nil {
+			set["retention.keep_saved"] = *retention.KeepSaved
+		}
+	}
+
+	sourceCollection := database.OpenCollection(database.Client, "sources")
+
+	var source models.Source
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = sourceCollection.FindOneAndUpdate(ctx, bson.M{"_id": objectID}, bson.M{"$set": set}, opts).Decode(&source)
+	if err == mongo.ErrNoDocuments {
+		return nil, errors.New("source not found")
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to update source: %v", err)
+	}
+
+	return &source, nil
+}
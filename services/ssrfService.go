@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// blockedNetworks are address ranges the crawler must never reach:
+// loopback, RFC1918, link-local/metadata, CGNAT, documentation and reserved blocks,
+// plus the IPv6 ranges that embed an IPv4 address (IPv4-compatible, NAT64, 6to4, Teredo)
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.0.2.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"198.51.100.0/24",
+	"203.0.113.0/24",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/96",
+	"::1/128",
+	"64:ff9b::/96",
+	"64:ff9b:1::/48",
+	"100::/64",
+	"2001::/32",
+	"2001:db8::/32",
+	"2002::/16",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// isBlockedIP reports whether an address is private, loopback or reserved
+func isBlockedIP(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupIPAddr resolves host names; tests replace it so they don't depend on real DNS
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+func blockedAddressError(host string) error {
+	return &CrawlError{
+		Code:    CrawlErrorBlockedAddress,
+		Message: fmt.Sprintf("%s resolves to a private or reserved address", host),
+	}
+}
+
+// resolvePublicIPs resolves a host once and keeps only public addresses
+// Allowlisted hosts skip the address check
+func resolvePublicIPs(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		if isBlockedIP(ip) && !isHostAllowlisted(ctx, host) {
+			return nil, blockedAddressError(host)
+		}
+		return []net.IP{ip}, nil
+	}
+
+	addrs, err := lookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	allowlisted := isHostAllowlisted(ctx, host)
+
+	var ips []net.IP
+	for _, addr := range addrs {
+		if allowlisted || !isBlockedIP(addr.IP) {
+			ips = append(ips, addr.IP)
+		}
+	}
+
+	if len(ips) == 0 {
+		return nil, blockedAddressError(host)
+	}
+	return ips, nil
+}
+
+// safeDialContext dials only the addresses it validated itself, so a second
+// DNS lookup can't swap in a private address (DNS rebinding)
+func safeDialContext(dialer *net.Dialer) func(ctx context.Context, network string, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		// The DNS lookup counts against the connect timeout
+		lookupCtx, cancel := context.WithTimeout(ctx, dialer.Timeout)
+		ips, err := resolvePublicIPs(lookupCtx, host)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+
+		var lastErr error
+		for _, ip := range ips {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+}
+
+// checkRedirect validates every redirect hop before it is followed
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > crawlerLimits.MaxRedirects {
+		return &CrawlError{
+			Code:    CrawlErrorTooManyRedirects,
+			Message: fmt.Sprintf("stopped after %d redirects", crawlerLimits.MaxRedirects),
+		}
+	}
+	return validateFetchTarget(req.Context(), req.URL)
+}
+
+// validateFetchTarget rejects non-http(s) URLs and hosts that resolve to blocked addresses
+func validateFetchTarget(ctx context.Context, target *url.URL) error {
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return &CrawlError{
+			Code:    CrawlErrorBlockedAddress,
+			Message: fmt.Sprintf("unsupported URL scheme: %s", target.Scheme),
+		}
+	}
+
+	if target.Hostname() == "" {
+		return errors.New("URL has no host")
+	}
+
+	_, err := resolvePublicIPs(ctx, target.Hostname())
+	return classifyFetchError(err)
+}
+
+// ValidateSourceURL checks a user-supplied URL before it is stored as a source
+func ValidateSourceURL(ctx context.Context, target *url.URL) error {
+	err := validateFetchTarget(ctx, target)
+	if crawlErrorCode(err) == CrawlErrorBlockedAddress {
+		return errors.New("URL points to a private or reserved address")
+	}
+	if err != nil {
+		return fmt.Errorf("invalid URL: failed to resolve host: %v", err)
+	}
+	return nil
+}
+
+// newCrawlerTransport builds the hardened transport used for all crawler fetches
+// Environment proxies are ignored so requests can't be routed around the checks
+func newCrawlerTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   crawlerLimits.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	return &http.Transport{
+		Proxy:                 nil,
+		DialContext:           safeDialContext(dialer),
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   crawlerLimits.TLSTimeout,
+		ResponseHeaderTimeout: crawlerLimits.HeaderTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
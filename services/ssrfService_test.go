@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withAllowlist replaces the cached allowlist so the checks don't reach the database
+func withAllowlist(t *testing.T, hosts ...string) {
+	allowlistMu.Lock()
+	allowlistHosts = hosts
+	allowlistLoadedAt = time.Now()
+	allowlistMu.Unlock()
+
+	t.Cleanup(func() {
+		allowlistMu.Lock()
+		allowlistHosts = nil
+		allowlistLoadedAt = time.Time{}
+		allowlistMu.Unlock()
+	})
+}
+
+// withResolver answers host lookups from a fixed table; unknown hosts don't resolve
+func withResolver(t *testing.T, hosts map[string][]string) {
+	saved := lookupIPAddr
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		var addrs []net.IPAddr
+		for _, ip := range hosts[host] {
+			addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+		}
+		if len(addrs) == 0 {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		return addrs, nil
+	}
+	t.Cleanup(func() { lookupIPAddr = saved })
+}
+
+func TestIsBlockedIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		blocked bool
+	}{
+		// IPv4 private, loopback and reserved
+		{"127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"172.31.255.255", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"224.0.0.1", true},
+		{"255.255.255.255", true},
+
+		// IPv6 loopback, link-local, unique local
+		{"::", true},
+		{"::1", true},
+		{"fe80::1", true},
+		{"fc00::1", true},
+		{"fd12:3456::1", true},
+
+		// IPv6 forms that embed an IPv4 address
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:10.0.0.1", true},
+		{"::127.0.0.1", true},
+		{"64:ff9b::a00:1", true},
+		{"2002:7f00:1::1", true},
+		{"2002:c0a8:101::1", true},
+		{"2001:0:4136:e378:8000:63bf:3fff:fdd2", true},
+
+		// Public
+		{"8.8.8.8", false},
+		{"93.184.216.34", false},
+		{"172.32.0.1", false},
+		{"::ffff:8.8.8.8", false},
+		{"2606:4700:4700::1111", false},
+		{"2a00:1450:4001::1", false},
+	}
+
+	for _, tt := range tests {
+		ip := net.ParseIP(tt.ip)
+		if ip == nil {
+			t.Fatalf("bad test address %q", tt.ip)
+		}
+		if got := isBlockedIP(ip); got != tt.blocked {
+			t.Errorf("isBlockedIP(%s) = %v, want %v", tt.ip, got, tt.blocked)
+		}
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+	withAllowlist(t, "10.0.0.5")
+
+	tests := []struct {
+		target string
+		code   string // expected CrawlError code, "" for allowed
+	}{
+		{"http://93.184.216.34/article", ""},
+		{"https://[2606:4700:4700::1111]/", ""},
+		{"http://10.0.0.5/allowlisted", ""},
+		{"http://127.0.0.1/", CrawlErrorBlockedAddress},
+		{"http://169.254.169.254/latest/meta-data/", CrawlErrorBlockedAddress},
+		{"http://[::1]:8080/", CrawlErrorBlockedAddress},
+		{"http://[::ffff:192.168.0.1]/", CrawlErrorBlockedAddress},
+		{"http://[2002:a00:1::1]/", CrawlErrorBlockedAddress},
+		{"file:///etc/passwd", CrawlErrorBlockedAddress},
+		{"gopher://93.184.216.34/", CrawlErrorBlockedAddress},
+		{"ftp://93.184.216.34/", CrawlErrorBlockedAddress},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest(http.MethodGet, tt.target, nil)
+		if err != nil {
+			t.Fatalf("bad test URL %q: %v", tt.target, err)
+		}
+
+		err = checkRedirect(req, []*http.Request{{}})
+		if tt.code == "" {
+			if err != nil {
+				t.Errorf("checkRedirect(%s) = %v, want nil", tt.target, err)
+			}
+			continue
+		}
+		if got := crawlErrorCode(err); got != tt.code {
+			t.Errorf("checkRedirect(%s) code = %q (%v), want %q", tt.target, got, err, tt.code)
+		}
+	}
+}
+
+func TestCheckRedirectLimit(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://93.184.216.34/", nil)
+	via := make([]*http.Request, crawlerLimits.MaxRedirects+1)
+
+	err := checkRedirect(req, via)
+	if got := crawlErrorCode(err); got != CrawlErrorTooManyRedirects {
+		t.Errorf("checkRedirect after %d hops code = %q, want %q", len(via), got, CrawlErrorTooManyRedirects)
+	}
+}
+
+func TestValidateSourceURL(t *testing.T) {
+	withAllowlist(t, "intranet.example")
+	withResolver(t, map[string][]string{
+		"news.example":     {"93.184.216.34"},
+		"mixed.example":    {"10.0.0.1", "93.184.216.34"},
+		"rebind.example":   {"127.0.0.1"},
+		"v6only.example":   {"fd00::1"},
+		"intranet.example": {"10.1.1.1"},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		target string
+		prefix string // expected error prefix, "" for valid
+	}{
+		{"https://93.184.216.34/news", ""},
+		{"http://192.168.1.10/", "URL points to a private or reserved address"},
+		{"http://[fd00::1]/", "URL points to a private or reserved address"},
+		{"https://news.example/feed", ""},
+		{"https://mixed.example/", ""},
+		{"https://intranet.example/", ""},
+		{"http://rebind.example/", "URL points to a private or reserved address"},
+		{"http://v6only.example/", "URL points to a private or reserved address"},
+		{"http://unresolvable.example/", "invalid URL"},
+	}
+
+	for _, tt := range tests {
+		target, err := url.Parse(tt.target)
+		if err != nil {
+			t.Fatalf("bad test URL %q: %v", tt.target, err)
+		}
+
+		err = ValidateSourceURL(ctx, target)
+		switch {
+		case tt.prefix == "" && err != nil:
+			t.Errorf("ValidateSourceURL(%s) = %v, want nil", tt.target, err)
+		case tt.prefix != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.prefix)):
+			t.Errorf("ValidateSourceURL(%s) = %v, want error starting with %q", tt.target, err, tt.prefix)
+		}
+	}
+}
@@ -31,6 +31,11 @@ func AddSubscription(ctx context.Context, userID string, urlString string) (*mod
 		return nil, errors.New("invalid URL format")
 	}
 
+	// Refuse URLs that point into our own network
+	if err := ValidateSourceURL(ctx, parsedURL); err != nil {
+		return nil, err
+	}
+
 	// Normalize URL (remove trailing slash)
 	normalizedURL := parsedURL.String()
 	if normalizedURL[len(normalizedURL)-1] == '/' {
@@ -47,6 +52,7 @@ func AddSubscription(ctx context.Context, userID string, urlString string) (*mod
 
 	if err == mongo.ErrNoDocuments {
 		// Source doesn't exist, create new one
+		createdAt := time.Now()
 		source = models.Source{
 			ID:               primitive.NewObjectID(),
 			URL:              normalizedURL,
@@ -55,6 +61,7 @@ func AddSubscription(ctx context.Context, userID string, urlString string) (*mod
 			LastCrawledAt:    nil,
 			LastAttemptAt:    nil,
 			LastError:        "",
+			NextCrawlAt:      &createdAt, // crawl new sources on the next scheduler tick
 			RSSUrl:           "",
 			SitemapUrl:       "",
 			ETag:             "",
@@ -62,8 +69,16 @@ func AddSubscription(ctx context.Context, userID string, urlString string) (*mod
 			TotalArticles:    0,
 			SuccessfulCrawls: 0,
 			FailedCrawls:     0,
-			CreatedAt:        time.Now(),
-			UpdatedAt:        time.Now(),
+			CreatedAt:        createdAt,
+			UpdatedAt:        createdAt,
+		}
+
+		// Find feeds, sitemaps and a proper name before saving
+		discovery, err := DiscoverSource(ctx, normalizedURL)
+		if err != nil {
+			log.Printf("Feed discovery failed for %s: %v", normalizedURL, err)
+		} else {
+			applyDiscovery(&source, discovery)
 		}
 
 		_, err = sourceCollection.InsertOne(ctx, source)